@@ -0,0 +1,2 @@
+{{define "content"}}<h1>{{.title}}</h1>
+{{include "widgets/broken"}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{range .items}}{{partialCached "widgets/count" . "same"}}{{end}}{{end}}
\ No newline at end of file
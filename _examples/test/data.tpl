@@ -0,0 +1 @@
+{{define "content"}}{{with data.GetJSON .json .headers}}{{.name}}:{{range .tags}} {{.}}{{end}}{{end}}|{{range data.GetCSV ";" .csv}}{{index . 0}}={{index . 1}},{{end}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{cache "sidebar" "5m" "widgets/count" .}}{{end}}
\ No newline at end of file
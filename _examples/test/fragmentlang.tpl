@@ -0,0 +1 @@
+{{define "content"}}{{cache "greeting" "5m" "widgets/greeting" .}}{{end}}
\ No newline at end of file
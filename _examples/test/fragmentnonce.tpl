@@ -0,0 +1 @@
+{{define "content"}}{{cache "nonce" "5m" "widgets/nonce" .}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{humanize.Comma .n}} {{humanize.Ordinal 3}} {{humanize.Bytes 1536000}} {{humanize.SIPrefix 1234567}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{with images.Config "img/photo.jpg"}}<img width="{{.Width}}" height="{{.Height}}">{{end}}{{with images.EXIF "img/photo.jpg"}}{{.Date.Year}}{{else}}none{{end}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}<img src="{{(images.Fill "50x50 jpg" "img/a.png").RelPermalink}}">{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{inflect.Pluralize "category"}} {{inflect.Singularize "People"}} {{inflect.PluralizeWithCount .n "person"}}{{end}}
\ No newline at end of file
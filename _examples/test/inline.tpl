@@ -0,0 +1 @@
+{{define "content"}}<img src="{{images.Inline (or .path "img/a.png")}}">{{end}}
\ No newline at end of file
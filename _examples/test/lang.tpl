@@ -0,0 +1 @@
+{{define "content"}}{{lang.Language}}|{{T "greeting" "name" .name}}|{{lang.Translate "nav.home"}}|{{T "items" "count" .count}}|{{T "missing"}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{include "widgets/loop"}}{{end}}
\ No newline at end of file
@@ -0,0 +1,11 @@
+{{define "content"}}
+  <!-- comment -->
+  <ul class="a  b">
+    <li>  one  </li>
+    <li><b>two</b> <i>three</i></li>
+  </ul>
+  <pre>
+  keep   this
+  </pre>
+  <style>{{minifyTrustedCSS ".x { color: red; }"}}</style>
+{{end}}
@@ -0,0 +1 @@
+{{define "content"}}{{.name}}{{end}}
\ No newline at end of file
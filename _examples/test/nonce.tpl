@@ -0,0 +1 @@
+{{define "content"}}<script nonce="{{cspNonce}}"></script>{{end}}
\ No newline at end of file
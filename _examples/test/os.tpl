@@ -0,0 +1 @@
+{{define "content"}}{{os.ReadFile "/CHANGELOG.md"}}|{{range os.ReadDir "docs"}}{{.Name}}:{{.Size}}{{if .IsDir}}/{{end}} {{end}}{{end}}
\ No newline at end of file
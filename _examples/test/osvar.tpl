@@ -0,0 +1 @@
+{{define "content"}}{{$os := os}}{{$os.ReadFile "/CHANGELOG.md"}}{{end}}
\ No newline at end of file
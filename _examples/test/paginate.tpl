@@ -0,0 +1 @@
+{{define "content"}}{{$p := paginate .items 2 .page "/posts/{page}"}}{{range $p.PageItems}}[{{.}}]{{end}}{{pagination $p}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{$p := paginate .items 2 .page}}{{$p.PrevURL}}|{{$p.NextURL}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{partial "widgets/card" .user}}{{end}}
\ No newline at end of file
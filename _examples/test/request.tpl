@@ -0,0 +1 @@
+{{define "content"}}{{request.Method}} {{request.Path}} {{request.Query "page"}} {{if request.HasPathPrefix "/blog"}}active{{end}} {{if request.IsPath "/blog/"}}home{{end}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{$css := resources.Get "css/app.css" | resources.Minify | resources.Fingerprint}}<link href="{{$css.RelPermalink}}" integrity="{{$css.Integrity}}">{{$js := resources.Concat "js/bundle.js" (resources.Get "js/a.js") (resources.Get "js/b.js")}}<script src="{{$js.RelPermalink}}"></script>{{end}}
@@ -0,0 +1 @@
+{{define "content"}}{{$s := newScratch}}{{range .items}}{{$s.Add "total" .price}}{{$s.Add "tags" .tag}}{{$s.SetInMap "byName" .name .price}}{{end}}{{$s.Get "total"}} {{$s.Get "tags"}} {{$s.GetSortedMapValues "byName"}}{{end}}
\ No newline at end of file
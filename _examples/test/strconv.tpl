@@ -0,0 +1 @@
+{{define "content"}}{{strconv.Quote .s}} {{strconv.FormatInt 255 16}} {{if strconv.ParseBool "TRUE"}}yes{{end}} {{strconv.Unquote "\"a\\tb\""}}{{end}}
\ No newline at end of file
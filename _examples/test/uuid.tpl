@@ -0,0 +1 @@
+{{define "content"}}{{with uuid.Parse .id}}{{.}} v{{.Version}}{{end}} {{uuid.IsValid "nope"}} {{len uuid.New}}{{end}}
\ No newline at end of file
@@ -0,0 +1,4 @@
+<ul>
+{{range .items}}<li>{{.}}</li>{{end}}
+</ul>
+{{index .title 10}}
\ No newline at end of file
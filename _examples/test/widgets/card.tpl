@@ -0,0 +1 @@
+Card{{.name}}
\ No newline at end of file
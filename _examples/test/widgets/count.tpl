@@ -0,0 +1 @@
+{{count}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{T "greeting" "name" .name}}
\ No newline at end of file
@@ -0,0 +1 @@
+{{include "widgets/loop"}}
\ No newline at end of file
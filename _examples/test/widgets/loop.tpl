@@ -0,0 +1 @@
+{{partial "widgets/loop-partial" .}}
\ No newline at end of file
@@ -0,0 +1 @@
+<script nonce="{{cspNonce}}"></script>
\ No newline at end of file
@@ -0,0 +1 @@
+{{define "content"}}{{include "widgets/card"}} {{humanize.Comma 1000}} {{upper .title}}{{end}}
\ No newline at end of file
@@ -0,0 +1 @@
+<html>{{template "content" .}}</html>
\ No newline at end of file
@@ -0,0 +1,4 @@
+<div>
+{{if .title}}
+{{.title}}
+</div>
\ No newline at end of file
@@ -0,0 +1,2 @@
+<p>
+{{missing .}}</p>
\ No newline at end of file
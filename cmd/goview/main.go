@@ -0,0 +1,274 @@
+// Command goview works with goview templates outside of an application, such as in CI pipelines.
+//
+// Usage:
+//
+//	goview validate [flags] [root]
+//	goview render --template page.tmpl [--data data.json] [--layout layouts/base.tmpl] [flags]
+//	goview funcs [-format markdown|json] [flags]
+//
+// The validate command parses every template under root, "views" by default, and reports
+// all parse errors with file and line. It exits with status 1 when a template is invalid.
+//
+// The render command renders the template with the JSON data and the optional master layout,
+// for previewing templates and generating static pages in build scripts.
+//
+// The funcs command writes the reference of the template functions with their signatures and docs.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/epikur-io/goview"
+)
+
+const usage = `usage: goview <command> [flags]
+
+commands:
+  validate  parse all templates and report errors with file and line
+  render    render a template with JSON data
+  funcs     write the reference of the template functions as Markdown or JSON
+
+run "goview <command> -h" for the flags of a command
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run run the command of args, returning the exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "validate":
+		return validate(args[1:], stdout, stderr)
+	case "render":
+		return render(args[1:], stdin, stdout, stderr)
+	case "funcs":
+		return funcs(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	fmt.Fprintf(stderr, "goview: unknown command %q\n\n%v", args[0], usage)
+	return 2
+}
+
+// engineFlags flags of the engine config shared by the commands
+type engineFlags struct {
+	funcs  string
+	delims string
+	text   bool
+}
+
+func (f *engineFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.funcs, "funcs", "", "comma separated names of application functions, such as \"sub,copy\"")
+	fs.StringVar(&f.delims, "delims", "", "comma separated left and right delimiters, such as \"[[,]]\"")
+	fs.BoolVar(&f.text, "text", false, "use text/template without HTML escaping")
+}
+
+// config get the engine config of the root, application functions are stubs returning nothing
+func (f *engineFlags) config(root, ext string) (goview.Config, error) {
+	config := goview.Config{
+		Root:      root,
+		Extension: ext,
+		Funcs:     make(template.FuncMap),
+		Delims:    goview.DefaultConfig.Delims,
+		TextMode:  f.text,
+	}
+	for _, name := range strings.Split(f.funcs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config.Funcs[name] = func(...any) string { return "" }
+		}
+	}
+	if f.delims != "" {
+		left, right, ok := strings.Cut(f.delims, ",")
+		if !ok || left == "" || right == "" {
+			return config, fmt.Errorf("invalid delimiters: %q", f.delims)
+		}
+		config.Delims = goview.Delims{Left: left, Right: right}
+	}
+	return config, nil
+}
+
+func validate(args []string, stdout, stderr io.Writer) int {
+	var ef engineFlags
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: goview validate [flags] [root]")
+		fs.PrintDefaults()
+	}
+	ext := fs.String("ext", goview.DefaultConfig.Extension, "template file extension")
+	ef.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	root := goview.DefaultConfig.Root
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+	config, err := ef.config(root, *ext)
+	if err != nil {
+		fmt.Fprintf(stderr, "goview: %v\n", err)
+		return 2
+	}
+
+	gv := goview.New(config)
+	errs := gv.ValidateAll()
+	for _, err := range errs {
+		fmt.Fprintln(stderr, err)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+	names, _ := gv.TemplateNames()
+	fmt.Fprintf(stdout, "%v templates ok\n", len(names))
+	return 0
+}
+
+func render(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var ef engineFlags
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: goview render --template page.tmpl [--data data.json] [--layout layouts/base.tmpl] [flags]")
+		fs.PrintDefaults()
+	}
+	root := fs.String("root", ".", "template root, template, layout and partials are relative to it")
+	tpl := fs.String("template", "", "template file to render")
+	layout := fs.String("layout", "", "master layout file, empty for none")
+	partials := fs.String("partials", "", "comma separated partial files, such as \"partials/head.tmpl,partials/foot.tmpl\"")
+	dataFile := fs.String("data", "", "JSON data file, \"-\" for stdin, empty for no data")
+	out := fs.String("o", "", "output file, empty for stdout")
+	ef.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *tpl == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := renderFile(&ef, *root, *tpl, *layout, *partials, *dataFile, *out, stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "goview: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// renderFile render the template file with the layout file, partial files and JSON data
+func renderFile(ef *engineFlags, root, tpl, layout, partials, dataFile, out string, stdin io.Reader, stdout io.Writer) error {
+	ext := filepath.Ext(tpl)
+	config, err := ef.config(root, ext)
+	if err != nil {
+		return err
+	}
+	name, err := templateName(root, tpl, ext)
+	if err != nil {
+		return err
+	}
+	if layout != "" {
+		if config.Master, err = templateName(root, layout, ext); err != nil {
+			return err
+		}
+	}
+	for _, p := range strings.Split(partials, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		partial, err := templateName(root, p, ext)
+		if err != nil {
+			return err
+		}
+		config.Partials = append(config.Partials, partial)
+	}
+
+	var data any
+	if dataFile != "" {
+		r := stdin
+		if dataFile != "-" {
+			f, err := os.Open(dataFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		if err := json.NewDecoder(r).Decode(&data); err != nil {
+			return fmt.Errorf("data %v: %v", dataFile, err)
+		}
+	}
+
+	// Render into a buffer, so no partial output file is left when rendering fails
+	buf := new(bytes.Buffer)
+	if err := goview.New(config).RenderWriter(buf, name, data); err != nil {
+		return err
+	}
+	if out != "" {
+		return os.WriteFile(out, buf.Bytes(), 0644)
+	}
+	_, err = buf.WriteTo(stdout)
+	return err
+}
+
+// templateName get the template name of the file relative to root, such as "layouts/base"
+func templateName(root, file, ext string) (string, error) {
+	if filepath.Ext(file) != ext {
+		return "", fmt.Errorf("template %v: extension must be %v", file, ext)
+	}
+	path := file
+	if filepath.IsAbs(file) {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+		if path, err = filepath.Rel(abs, file); err != nil {
+			return "", err
+		}
+	}
+	path = filepath.Clean(path)
+	if path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("template %v: outside of root %v", file, root)
+	}
+	return filepath.ToSlash(strings.TrimSuffix(path, ext)), nil
+}
+
+func funcs(args []string, stdout, stderr io.Writer) int {
+	var ef engineFlags
+	fs := flag.NewFlagSet("funcs", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: goview funcs [-format markdown|json] [flags]")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "markdown", "output format, \"markdown\" or \"json\"")
+	ef.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	config, err := ef.config(goview.DefaultConfig.Root, goview.DefaultConfig.Extension)
+	if err != nil {
+		fmt.Fprintf(stderr, "goview: %v\n", err)
+		return 2
+	}
+	if err := goview.New(config).WriteFuncReference(stdout, *format); err != nil {
+		fmt.Fprintf(stderr, "goview: %v\n", err)
+		return 1
+	}
+	return 0
+}
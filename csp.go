@@ -0,0 +1,60 @@
+package goview
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+type cspNonceKey struct{}
+
+// NewCSPNonce new random nonce for the Content-Security-Policy header.
+// It is URL-safe base64, so html/template doesn't escape it in attributes.
+func NewCSPNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ContextWithCSPNonce return a copy of ctx carrying the nonce
+func ContextWithCSPNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, cspNonceKey{}, nonce)
+}
+
+// CSPNonceFromContext get the nonce stored by CSPMiddleware or ContextWithCSPNonce, empty if none
+func CSPNonceFromContext(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// WithCSPNonce set the nonce returned by the `cspNonce` template function
+func WithCSPNonce(nonce string) RenderOption {
+	return func(ctx *RenderContext) {
+		ctx.CSPNonce = nonce
+	}
+}
+
+// CSPMiddleware net/http middleware generating a nonce per request, stored in the request context
+// and set in the Content-Security-Policy header, where `{nonce}` in the policy is replaced with it.
+// RenderRequest, Negotiate and Handler pass the nonce of the request to the `cspNonce` template function.
+//
+//	goview.CSPMiddleware("script-src 'nonce-{nonce}' 'strict-dynamic'; object-src 'none'")
+func CSPMiddleware(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce, err := NewCSPNonce()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if policy != "" {
+				w.Header().Set("Content-Security-Policy", strings.ReplaceAll(policy, "{nonce}", nonce))
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithCSPNonce(r.Context(), nonce)))
+		})
+	}
+}
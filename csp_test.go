@@ -0,0 +1,56 @@
+package goview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSPMiddleware(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	handler := CSPMiddleware("script-src 'nonce-{nonce}'")(gv.Handler("nonce", nil))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	policy := recorder.Header().Get("Content-Security-Policy")
+	nonce := strings.TrimSuffix(strings.TrimPrefix(policy, "script-src 'nonce-"), "'")
+	if nonce == "" || nonce == policy {
+		t.Fatalf("invalid policy: %v", policy)
+	}
+	assertRecorder(t, recorder, http.StatusOK, `<v><script nonce="`+nonce+`"></script></v>`)
+}
+
+func TestCSPNonce_RequestContext(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	// Renders with only WithRequest, such as the framework adapters, read the nonce of the request context
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(ContextWithCSPNonce(r.Context(), "abc"))
+	recorder := httptest.NewRecorder()
+	if err := gv.RenderWriter(recorder, "nonce", nil, WithRequest(r)); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if body := recorder.Body.String(); body != `<v><script nonce="abc"></script></v>` {
+		t.Errorf("actual: %v", body)
+	}
+}
+
+func TestNewCSPNonce(t *testing.T) {
+	a, err := NewCSPNonce()
+	if err != nil {
+		t.Fatalf("nonce error: %v", err)
+	}
+	b, _ := NewCSPNonce()
+	if a == b || len(a) != 22 {
+		t.Errorf("nonces: %v, %v", a, b)
+	}
+}
@@ -62,6 +62,9 @@
 // Gorice for Go.rice:
 // https://godoc.org/github.com/epikur-io/goview/supports/gorice
 //
+// Promview for Prometheus render metrics:
+// https://godoc.org/github.com/epikur-io/goview/supports/promview
+//
 // Examples:
 // https://github.com/epikur-io/goview/_examples
 package goview
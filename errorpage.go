@@ -0,0 +1,152 @@
+package goview
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// errorPageContext lines of template source shown around the failing line
+const errorPageContext = 5
+
+// RenderError error of a render, with the include chain of the failing template
+type RenderError struct {
+	Name  string   //rendered template
+	Chain []string //include chain down to the failing template, such as ["index", "widgets/list"]
+	Err   error
+}
+
+func (e *RenderError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// errorLocation matches template locations of parse and execute errors, such as "template: index:3:12:"
+var errorLocation = regexp.MustCompile(`template: ([^:\s]+):(\d+)(?::(\d+))?:`)
+
+// SourceLine line of template source on the error page
+type SourceLine struct {
+	Number int
+	Text   string
+	Error  bool
+}
+
+// ErrorPage context of the developer error page
+type ErrorPage struct {
+	Name   string       //rendered template
+	Error  string       //error message
+	File   string       //template of the error location, empty if unknown
+	Line   int          //line of the error location
+	Column int          //column of the error location, 0 if unknown
+	Source []SourceLine //source around the error line
+	Chain  []string     //include chain down to the failing template
+	Keys   []string     //keys of the render data
+	Method string
+	URL    string
+}
+
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Render error: {{.Name}}</title>
+<style>
+body{margin:0;font:14px/1.5 system-ui,sans-serif;color:#222;background:#f6f6f6}
+header{padding:24px 32px;background:#b3261e;color:#fff}
+header h1{margin:0 0 8px;font-size:20px}
+header pre{margin:0;white-space:pre-wrap;word-break:break-word;font:13px/1.5 ui-monospace,monospace}
+section{margin:24px 32px;padding:16px 24px;background:#fff;border:1px solid #ddd}
+h2{margin:0 0 12px;font-size:15px}
+table{border-collapse:collapse;width:100%;font:13px/1.6 ui-monospace,monospace}
+td{padding:0 8px;white-space:pre}
+td.n{width:1%;text-align:right;color:#999}
+tr.e{background:#fde7e6}
+tr.e td.n{color:#b3261e;font-weight:bold}
+code{font:13px ui-monospace,monospace}
+</style></head><body>
+<header><h1>Render error: {{.Name}}</h1><pre>{{.Error}}</pre></header>
+{{if .File}}<section><h2>{{.File}}:{{.Line}}{{if .Column}}:{{.Column}}{{end}}</h2>
+<table>{{range .Source}}<tr{{if .Error}} class="e"{{end}}><td class="n">{{.Number}}</td><td>{{.Text}}</td></tr>{{end}}</table></section>{{end}}
+{{if .Chain}}<section><h2>Include chain</h2><code>{{range $i, $v := .Chain}}{{if $i}} &rarr; {{end}}{{$v}}{{end}}</code></section>{{end}}
+<section><h2>Data keys</h2>{{if .Keys}}<code>{{range $i, $v := .Keys}}{{if $i}}, {{end}}{{$v}}{{end}}</code>{{else}}none{{end}}</section>
+{{if .Method}}<section><h2>Request</h2><code>{{.Method}} {{.URL}}</code></section>{{end}}
+</body></html>
+`))
+
+// NewErrorPage get the developer error page of the render error, with the template source around the
+// error location, the include chain and the keys of the render data. r may be nil.
+func (e *ViewEngine) NewErrorPage(r *http.Request, name string, data any, err error) *ErrorPage {
+	page := &ErrorPage{Name: name, Error: err.Error(), Keys: dataKeys(data)}
+	if rerr, ok := err.(*RenderError); ok {
+		page.Chain = rerr.Chain
+	}
+	if r != nil {
+		page.Method, page.URL = r.Method, r.URL.RequestURI()
+	}
+
+	// The last location is the innermost, included templates fail inside the error of their parent
+	matches := errorLocation.FindAllStringSubmatch(page.Error, -1)
+	if len(matches) == 0 {
+		return page
+	}
+	m := matches[len(matches)-1]
+	source, ferr := e.fileHandler(e.config, m[1])
+	if ferr != nil {
+		return page
+	}
+	page.File = m[1]
+	page.Line, _ = strconv.Atoi(m[2])
+	page.Column, _ = strconv.Atoi(m[3])
+	lines := strings.Split(source, "\n")
+	from, to := max(page.Line-errorPageContext, 1), min(page.Line+errorPageContext, len(lines))
+	for i := from; i <= to; i++ {
+		page.Source = append(page.Source, SourceLine{Number: i, Text: lines[i-1], Error: i == page.Line})
+	}
+	return page
+}
+
+// WriteErrorPage write the developer error page of the render error with status 500, see NewErrorPage.
+// It shows template source and data keys, use it in development only.
+func (e *ViewEngine) WriteErrorPage(w http.ResponseWriter, r *http.Request, name string, data any, err error) error {
+	buf := new(bytes.Buffer)
+	if err := errorPageTemplate.Execute(buf, e.NewErrorPage(r, name, data, err)); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// dataKeys get the sorted keys of a map, or the exported fields of a struct
+func dataKeys(data any) []string {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	var keys []string
+	switch v.Kind() {
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		sort.Strings(keys)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() {
+				keys = append(keys, f.Name)
+			}
+		}
+	}
+	return keys
+}
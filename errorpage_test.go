@@ -0,0 +1,73 @@
+package goview
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestViewEngine_ErrorPage(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	data := M{"title": "<b>", "items": []string{"a"}}
+	err := gv.RenderWriter(new(bytes.Buffer), "broken", data)
+	var rerr *RenderError
+	if !errors.As(err, &rerr) || strings.Join(rerr.Chain, " ") != "broken widgets/broken" {
+		t.Fatalf("expect render error with include chain, actual: %#v", err)
+	}
+
+	page := gv.NewErrorPage(nil, "broken", data, err)
+	if page.File != "widgets/broken" || page.Line != 4 || page.Column == 0 {
+		t.Errorf("location: %v:%v:%v", page.File, page.Line, page.Column)
+	}
+	if len(page.Source) != 4 || !page.Source[3].Error || page.Source[3].Text != "{{index .title 10}}" {
+		t.Errorf("source: %+v", page.Source)
+	}
+	if strings.Join(page.Keys, ",") != "items,title" {
+		t.Errorf("keys: %v", page.Keys)
+	}
+
+	// Handler writes the error page in debug mode only
+	for _, debug := range []bool{false, true} {
+		gv := New(Config{
+			Root:      "_examples/test",
+			Extension: ".tpl",
+			Master:    "layouts/master",
+			Debug:     debug,
+		})
+		rec := httptest.NewRecorder()
+		gv.Handler("broken", func(*http.Request) any { return data }).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/page?x=1", nil))
+		body := rec.Body.String()
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("debug: %v, status: %v", debug, rec.Code)
+		}
+		if debug != strings.Contains(body, "widgets/broken:4:") {
+			t.Errorf("debug: %v, body: %v", debug, body)
+		}
+		if debug && (!strings.Contains(body, "GET /page?x=1") || strings.Contains(body, "<b>")) {
+			t.Errorf("expect escaped error page with request, body: %v", body)
+		}
+	}
+}
+
+func TestDataKeys(t *testing.T) {
+	type page struct {
+		Title string
+		body  string
+	}
+	if keys := dataKeys(&page{}); len(keys) != 1 || keys[0] != "Title" {
+		t.Errorf("struct keys: %v", keys)
+	}
+	if keys := dataKeys(map[int]string{2: "", 1: ""}); strings.Join(keys, ",") != "1,2" {
+		t.Errorf("map keys: %v", keys)
+	}
+	if keys := dataKeys(nil); keys != nil {
+		t.Errorf("nil keys: %v", keys)
+	}
+}
@@ -0,0 +1,99 @@
+package goview
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithLastModified set the Last-Modified time of the rendered page, see RenderRequest
+func WithLastModified(t time.Time) RenderOption {
+	return func(ctx *RenderContext) {
+		ctx.LastModified = t
+	}
+}
+
+// RenderRequest render template for the request. With Config.ETag enabled the body is hashed
+// into an ETag header, and 304 Not Modified is answered when If-None-Match matches it,
+// or If-Modified-Since is not before the time set with WithLastModified.
+func (e *ViewEngine) RenderRequest(w http.ResponseWriter, r *http.Request, statusCode int, name string, data any, opts ...RenderOption) error {
+	opts = e.requestOptions(r, opts)
+	if !e.config.ETag && !e.config.Trace {
+		return e.Render(w, statusCode, name, data, opts...)
+	}
+	return e.renderBuffered(w, r, statusCode, name, data, opts...)
+}
+
+// renderBuffered render template into a buffer before writing the response, so nothing is
+// written when rendering fails. The ETag is set and checked with Config.ETag enabled,
+// and the trace recorded with Config.Trace enabled.
+func (e *ViewEngine) renderBuffered(w http.ResponseWriter, r *http.Request, statusCode int, name string, data any, opts ...RenderOption) error {
+	header := w.Header()
+	if e.config.Trace {
+		trace := NewTrace(name)
+		trace.Method, trace.URL = r.Method, r.URL.RequestURI()
+		opts = append(opts, WithTrace(trace))
+		defer e.traces.add(trace)
+		header.Set(TraceHeader, trace.ID)
+	}
+
+	buf := new(bytes.Buffer)
+	state := newRenderState()
+	if err := e.executeRender(buf, name, data, state, opts...); err != nil {
+		return err
+	}
+
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = e.ContentType()
+	}
+	if !e.config.ETag {
+		w.WriteHeader(statusCode)
+		_, err := buf.WriteTo(w)
+		return err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	// The render context of the page carries the time set with WithLastModified
+	var lastModified time.Time
+	if state.root != nil {
+		lastModified = state.root.LastModified
+	}
+
+	header.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if statusCode == http.StatusOK && (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		notModified(r, etag, lastModified) {
+		header.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.WriteHeader(statusCode)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// notModified check the conditional request headers, If-None-Match takes precedence over If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, v := range strings.Split(inm, ",") {
+			v = strings.TrimSpace(v)
+			if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
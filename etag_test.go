@@ -0,0 +1,67 @@
+package goview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestViewEngine_RenderRequest(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		ETag:      true,
+	})
+	modified := time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+	if err := gv.RenderRequest(recorder, req, http.StatusOK, "index", M{}, WithLastModified(modified)); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	etag := recorder.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("ETag header is empty")
+	}
+	if val := recorder.Header().Get("Last-Modified"); val != "Sun, 15 Jan 2023 10:00:00 GMT" {
+		t.Errorf("actual: %v, expect: %v", val, "Sun, 15 Jan 2023 10:00:00 GMT")
+	}
+	assertRecorder(t, recorder, http.StatusOK, "<v>Index</v>")
+
+	for _, v := range []struct {
+		Header string
+		Value  string
+		Status int
+		Out    string
+	}{
+		{Header: "If-None-Match", Value: etag, Status: http.StatusNotModified},
+		{Header: "If-None-Match", Value: `"other", W/` + etag, Status: http.StatusNotModified},
+		{Header: "If-None-Match", Value: `"other"`, Status: http.StatusOK, Out: "<v>Index</v>"},
+		{Header: "If-Modified-Since", Value: "Sun, 15 Jan 2023 10:00:00 GMT", Status: http.StatusNotModified},
+		{Header: "If-Modified-Since", Value: "Sat, 14 Jan 2023 10:00:00 GMT", Status: http.StatusOK, Out: "<v>Index</v>"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(v.Header, v.Value)
+		recorder := httptest.NewRecorder()
+		if err := gv.RenderRequest(recorder, req, http.StatusOK, "index", M{}, WithLastModified(modified)); err != nil {
+			t.Errorf("render error: %v", err)
+			continue
+		}
+		assertRecorder(t, recorder, v.Status, v.Out)
+	}
+	// Options are applied once, to the render context of the page
+	applied := 0
+	counted := func(ctx *RenderContext) {
+		applied++
+		ctx.LastModified = modified
+	}
+	recorder = httptest.NewRecorder()
+	if err := gv.RenderRequest(recorder, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, "index", M{}, counted); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if applied != 1 || recorder.Header().Get("Last-Modified") != "Sun, 15 Jan 2023 10:00:00 GMT" {
+		t.Errorf("applied: %v, last modified: %v", applied, recorder.Header().Get("Last-Modified"))
+	}
+}
@@ -0,0 +1,171 @@
+package goview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FragmentStore rendered fragment store interface, implement it for shared backends such as Redis
+type FragmentStore interface {
+	// Get get the fragment, ok is false when it is missing or expired
+	Get(key string) (value []byte, ok bool, err error)
+	// Set set the fragment, expiring after ttl
+	Set(key string, value []byte, ttl time.Duration) error
+	// DeletePrefix delete all fragments with keys starting with prefix
+	DeletePrefix(prefix string) error
+}
+
+const (
+	fragmentKeyPrefix = "goview:fragment:"
+	partialKeyPrefix  = "goview:partial:"
+)
+
+// SetFragmentStore set the store of the `cache` template function and partialCached with Config.PartialCacheTTL
+func (e *ViewEngine) SetFragmentStore(store FragmentStore) {
+	if store == nil {
+		panic("FragmentStore can't set nil!")
+	}
+	e.fragments = store
+}
+
+// InvalidateFragment delete every cached variant of the named fragment
+func (e *ViewEngine) InvalidateFragment(name string) error {
+	return e.fragments.DeletePrefix(fragmentKeyPrefix + name + "\x00")
+}
+
+// InvalidateFragments delete all cached fragments and partials
+func (e *ViewEngine) InvalidateFragments() error {
+	if err := e.fragments.DeletePrefix(fragmentKeyPrefix); err != nil {
+		return err
+	}
+	return e.fragments.DeletePrefix(partialKeyPrefix)
+}
+
+// fragment render the template with ctx as data, cached by name and a hash of the layout, the render
+// language and the JSON encoding of ctx for ttl, so equal values behind different pointers share the
+// fragment. A ctx that can't be encoded as JSON fails. Fragments are shared by all requests, so
+// `cspNonce` and `request` fail inside them.
+func (e *ViewEngine) fragment(name string, ttl any, layout string, ctx any, language string, state *renderState, opts ...RenderOption) (template.HTML, error) {
+	duration, err := toDuration(ttl)
+	if err != nil {
+		return "", fmt.Errorf("ViewEngine cache name:%v, error: %v", name, err)
+	}
+	encoded, err := json.Marshal(ctx)
+	if err != nil {
+		return "", fmt.Errorf("ViewEngine cache name:%v, error: context can't be the cache key: %v", name, err)
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v\x00%v\x00%s", layout, language, encoded)
+	key := fragmentKeyPrefix + name + "\x00" + strconv.FormatUint(h.Sum64(), 16)
+
+	return e.cachedFragment(key, duration, func() (template.HTML, error) {
+		state.cached++
+		defer func() { state.cached-- }()
+		return e.partial(layout, []any{ctx}, state, opts...)
+	})
+}
+
+// cachedFragment get the fragment from the store, or render and store it.
+// Store errors are logged and the fragment rendered uncached.
+func (e *ViewEngine) cachedFragment(key string, ttl time.Duration, render func() (template.HTML, error)) (template.HTML, error) {
+	value, ok, err := e.fragments.Get(key)
+	if err != nil {
+		e.logger.Error("goview: fragment store get failed", "key", key, "error", err)
+	} else if ok {
+		return template.HTML(value), nil
+	}
+
+	out, err := render()
+	if err != nil {
+		return "", err
+	}
+	if err := e.fragments.Set(key, []byte(out), ttl); err != nil {
+		e.logger.Error("goview: fragment store set failed", "key", key, "error", err)
+	}
+	return out, nil
+}
+
+// toDuration convert a time.Duration, duration string such as "5m", or seconds
+func toDuration(v any) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		return time.ParseDuration(d)
+	case int:
+		return time.Duration(d) * time.Second, nil
+	case int64:
+		return time.Duration(d) * time.Second, nil
+	}
+	return 0, fmt.Errorf("invalid duration: %v", v)
+}
+
+// MemoryFragmentStore in-memory fragment store, the default store of the engine
+type MemoryFragmentStore struct {
+	mu        sync.RWMutex
+	entries   map[string]fragmentEntry
+	lastSweep time.Time
+}
+
+type fragmentEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+var _ FragmentStore = (*MemoryFragmentStore)(nil)
+
+// NewMemoryFragmentStore new in-memory fragment store
+func NewMemoryFragmentStore() *MemoryFragmentStore {
+	return &MemoryFragmentStore{
+		entries:   make(map[string]fragmentEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get implement FragmentStore
+func (s *MemoryFragmentStore) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implement FragmentStore
+func (s *MemoryFragmentStore) Set(key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Drop expired entries once in a while so variant keys don't accumulate forever
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.entries {
+			if now.After(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[key] = fragmentEntry{value: bytes.Clone(value), expires: now.Add(ttl)}
+	return nil
+}
+
+// DeletePrefix implement FragmentStore
+func (s *MemoryFragmentStore) DeletePrefix(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range s.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(s.entries, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package goview
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestViewEngine_Fragment(t *testing.T) {
+	count := 0
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		Funcs: template.FuncMap{
+			"count": func() int {
+				count++
+				return count
+			},
+		},
+	})
+
+	render := func(data M, expect string) {
+		t.Helper()
+		buff := new(bytes.Buffer)
+		if err := gv.RenderWriter(buff, "fragment", data); err != nil {
+			t.Errorf("render error: %v", err)
+			return
+		}
+		if val := buff.String(); val != expect {
+			t.Errorf("actual: %v, expect: %v", val, expect)
+		}
+	}
+
+	render(M{"id": 1}, "<v>1</v>")
+	render(M{"id": 1}, "<v>1</v>")
+	render(M{"id": 2}, "<v>2</v>")
+
+	if err := gv.InvalidateFragment("sidebar"); err != nil {
+		t.Fatalf("invalidate error: %v", err)
+	}
+	render(M{"id": 1}, "<v>3</v>")
+	render(M{"id": 2}, "<v>4</v>")
+
+	// The key is the encoded value, not the pointer address
+	type item struct{ ID int }
+	render(M{"item": &item{ID: 1}}, "<v>5</v>")
+	render(M{"item": &item{ID: 1}}, "<v>5</v>")
+	render(M{"item": &item{ID: 2}}, "<v>6</v>")
+
+	if err := gv.RenderWriter(new(bytes.Buffer), "fragment", M{"fn": func() {}}); err == nil {
+		t.Error("cache with a context that can't be encoded is ok?")
+	}
+}
+
+func TestViewEngine_FragmentRequest(t *testing.T) {
+	i18n := NewI18n("en")
+	if err := i18n.LoadDir("_examples/test/i18n"); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	gv.SetI18n(i18n)
+
+	// Fragments are cached per render language
+	for _, v := range []struct {
+		Language string
+		Out      string
+	}{
+		{Language: "de", Out: "<v>Hallo GoView!</v>"},
+		{Language: "en", Out: "<v>Hello GoView!</v>"},
+		{Language: "de", Out: "<v>Hallo GoView!</v>"},
+	} {
+		buff := new(bytes.Buffer)
+		if err := gv.RenderWriter(buff, "fragmentlang", M{"name": "GoView"}, WithLanguage(v.Language)); err != nil {
+			t.Fatalf("render error: %v", err)
+		}
+		if val := buff.String(); val != v.Out {
+			t.Errorf("language: %v, actual: %v, expect: %v", v.Language, val, v.Out)
+		}
+	}
+
+	// Request dependent output can't be shared by all requests
+	err := gv.RenderWriter(new(bytes.Buffer), "fragmentnonce", nil, WithCSPNonce("abc"))
+	if err == nil || !strings.Contains(err.Error(), "cspNonce can't be used in cached fragments") {
+		t.Errorf("expect cspNonce error, actual: %v", err)
+	}
+}
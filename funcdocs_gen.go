@@ -0,0 +1,85 @@
+// Code generated by go run ./internal/funcdocs; DO NOT EDIT.
+
+package goview
+
+// funcDocs docs of the template functions and namespace methods, keyed by function name
+// or namespace type and method, such as "HumanizeNamespace.Comma"
+var funcDocs = map[string]string{
+	"DataNamespace.GetCSV":                "GetCSV fetch and parse the CSV document of the URL with the separator, such as \",\", with optional request headers",
+	"DataNamespace.GetJSON":               "GetJSON fetch and decode the JSON document of the URL, with optional request headers",
+	"HumanizeNamespace.Bytes":             "Bytes format the byte size with SI units, such as \"1.5 MB\"",
+	"HumanizeNamespace.Comma":             "Comma format the number with comma grouping separators, such as \"1,234,567\"",
+	"HumanizeNamespace.IBytes":            "IBytes format the byte size with IEC units, such as \"1.5 MiB\"",
+	"HumanizeNamespace.Ordinal":           "Ordinal format the integer with its English ordinal suffix, such as \"3rd\"",
+	"HumanizeNamespace.SIPrefix":          "SIPrefix format the number with an SI prefix, such as \"1.2M\"",
+	"ImagesNamespace.Config":              "Config get the dimensions and format of the image, see Resources.ImageConfig",
+	"ImagesNamespace.Crop":                "Crop crop the image to the size of the spec, see Resources.Crop",
+	"ImagesNamespace.EXIF":                "EXIF get the EXIF metadata of the JPEG image, nil if it has none, see Resources.EXIF",
+	"ImagesNamespace.Fill":                "Fill scale and crop the image to the size of the spec, see Resources.Fill",
+	"ImagesNamespace.Fit":                 "Fit scale the image down to fit into the box of the spec, see Resources.Fit",
+	"ImagesNamespace.Inline":              "Inline get the image as data URI, see Resources.Inline",
+	"ImagesNamespace.QRCode":              "QRCode get the QR code of the text as inline SVG of size pixels, such as an URL for tickets or 2FA enrollment",
+	"ImagesNamespace.QRCodePNG":           "QRCodePNG get the QR code of the text as PNG data URI of size pixels, for HTML emails",
+	"ImagesNamespace.Resize":              "Resize scale the image to the size of the spec, see Resources.Resize",
+	"InflectNamespace.Pluralize":          "Pluralize get the plural of the English word, such as \"people\" for \"person\"",
+	"InflectNamespace.PluralizeWithCount": "PluralizeWithCount prefix the word with the count, plural unless the count is 1, such as \"3 people\"",
+	"InflectNamespace.Singularize":        "Singularize get the singular of the English word, such as \"person\" for \"people\"",
+	"LangNamespace.FormatCurrency":        "FormatCurrency format the amount in the currency, an ISO 4217 code such as \"EUR\", with the currency digits, symbol and number format of the render language from the CLDR data of golang.org/x/text, such as `€ 19.99` for en and `€ 19,99` for de.",
+	"LangNamespace.FormatNumber":          "FormatNumber format the number with precision decimals, using the decimal and grouping separators of the render language, such as `1,234.56` for en and `1.234,56` for de.",
+	"LangNamespace.FormatNumberCustom":    "FormatNumberCustom format the number with precision decimals and custom separators, options are the minus sign, decimal point and grouping separator separated by spaces, `- . ,` by default. An empty grouping separator disables grouping.",
+	"LangNamespace.FormatPercent":         "FormatPercent format the percent value with precision decimals for the render language, such as `33.33%` for en and `33,33 %` for de. The value is in percent, 33.33 means 33.33%.",
+	"LangNamespace.Language":              "Language get the language of the render",
+	"LangNamespace.Match":                 "Match pick the language of the catalogs best matching the Accept-Language header value, see I18n.MatchLanguage",
+	"LangNamespace.T":                     "T alias of Translate",
+	"LangNamespace.Translate":             "Translate translate the message key, see I18n.Translate",
+	"OSNamespace.ReadDir":                 "ReadDir get the entries of the directory sorted by name, \"/\" or \".\" for the content root",
+	"OSNamespace.ReadFile":                "ReadFile get the content of the file, such as \"CHANGELOG.md\", files larger than MaxContentFileSize fail",
+	"RequestNamespace.HasPathPrefix":      "HasPathPrefix check if the request path is the prefix or below it, such as \"/blog\" for \"/blog/post\"",
+	"RequestNamespace.Header":             "Header get the first value of the request header",
+	"RequestNamespace.Host":               "Host get the request host, with port if present",
+	"RequestNamespace.IsPath":             "IsPath check if the cleaned request path is the path, ignoring a trailing slash",
+	"RequestNamespace.Method":             "Method get the request method, such as \"GET\"",
+	"RequestNamespace.Path":               "Path get the unescaped request path",
+	"RequestNamespace.Query":              "Query get the first value of the query parameter",
+	"RequestNamespace.QueryValues":        "QueryValues get all query parameters",
+	"RequestNamespace.Scheme":             "Scheme get \"https\" for TLS requests or requests forwarded as https, otherwise \"http\"",
+	"RequestNamespace.URL":                "URL get the escaped request path and query, such as \"/posts?page=2\"",
+	"ResourcesNamespace.Concat":           "Concat join the resources into one named name, see Resources.Concat",
+	"ResourcesNamespace.Fingerprint":      "Fingerprint copy the resource to a name containing its content hash, see Resources.Fingerprint",
+	"ResourcesNamespace.Get":              "Get get the file resource, see Resources.Get",
+	"ResourcesNamespace.Minify":           "Minify minify the resource with the engine minifier, see Resources.Minify",
+	"ResourcesNamespace.ToCSS":            "ToCSS compile the SCSS resource to css, see Resources.ToCSS",
+	"StrconvNamespace.FormatBool":         "FormatBool format the bool as \"true\" or \"false\"",
+	"StrconvNamespace.FormatInt":          "FormatInt format the integer in the base from 2 to 36, such as \"ff\" for 255 in base 16",
+	"StrconvNamespace.ParseBool":          "ParseBool parse \"1\", \"t\", \"true\", \"0\", \"f\", \"false\" and their upper case forms",
+	"StrconvNamespace.ParseFloat":         "ParseFloat parse the floating point number",
+	"StrconvNamespace.ParseInt":           "ParseInt parse the integer in the base, 0 detects the base from the prefix such as \"0x\"",
+	"StrconvNamespace.Quote":              "Quote quote the string as a Go string literal, which is also a valid JSON string for printable text",
+	"StrconvNamespace.QuoteToASCII":       "QuoteToASCII quote the string as a Go string literal, escaping non-ASCII characters",
+	"StrconvNamespace.Unquote":            "Unquote unquote the Go string, rune or raw string literal",
+	"T":                                   "T translate the key with the optional arguments, see lang.Translate",
+	"UUIDNamespace.IsValid":               "IsValid check if the string is a UUID, see ParseUUID",
+	"UUIDNamespace.New":                   "New new random version 4 UUID string",
+	"UUIDNamespace.NewV7":                 "NewV7 new time ordered version 7 UUID string",
+	"UUIDNamespace.Parse":                 "Parse parse the UUID, see ParseUUID",
+	"cache":                               "cache render the partial once and cache the output for ttl in the fragment store",
+	"cspNonce":                            "cspNonce get the CSP nonce of the render, or of the request context, see CSPMiddleware",
+	"data":                                "data get the namespace fetching remote JSON and CSV data, see SetDataOptions",
+	"humanize":                            "humanize get the namespace formatting numbers and sizes for humans",
+	"images":                              "images get the namespace processing image resources",
+	"include":                             "include render the template without master layout, with the render data",
+	"inflect":                             "inflect get the namespace pluralizing and singularizing words",
+	"lang":                                "lang get the translations namespace of the render language",
+	"minifyTrustedCSS":                    "minifyTrustedCSS minify the css with the engine minifier and mark it as safe template.CSS, only for trusted css, never for user input",
+	"minifyTrustedJS":                     "minifyTrustedJS minify the javascript with the engine minifier and mark it as safe template.JS, only for trusted javascript, never for user input",
+	"newScratch":                          "newScratch get a new scratch pad to set and add values while rendering",
+	"os":                                  "os get the namespace reading files of the content root, see SetContentFS",
+	"paginate":                            "paginate paginate the slice with the page size and page number, and the optional URL format. Default page URLs keep the query of the request.",
+	"pagination":                          "pagination render the builtin navigation of the paginator",
+	"partial":                             "partial render the template without master layout, with ctx as the only data",
+	"partialCached":                       "partialCached render the partial once per name and variants, see Config.PartialCacheTTL",
+	"request":                             "request get the request namespace, read only access to the rendered request",
+	"resources":                           "resources get the asset pipeline namespace, see SetResources",
+	"strconv":                             "strconv get the namespace quoting, parsing and formatting values",
+	"uuid":                                "uuid get the namespace generating and parsing UUIDs",
+}
@@ -0,0 +1,118 @@
+package goview
+
+//go:generate go run ./internal/funcdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FuncDoc reference of a template function or namespace method
+type FuncDoc struct {
+	Name      string `json:"name"`      //such as "partial" or "humanize.Comma"
+	Signature string `json:"signature"` //such as "humanize.Comma(any) string"
+	Doc       string `json:"doc,omitempty"`
+}
+
+// FuncReference get the reference of the template functions the templates may use, sorted by name.
+// Namespace functions, returning a pointer to a type named like HumanizeNamespace, are followed
+// by their methods. Functions of Config.Funcs are documented with Config.FuncDocs, keyed by
+// function name or "ns.Method".
+func (e *ViewEngine) FuncReference() []FuncDoc {
+	funcs := e.newRenderContext("", nil, false, newRenderState(), nil).Funcs
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ref []FuncDoc
+	for _, name := range names {
+		_, custom := e.config.Funcs[name]
+		t := reflect.TypeOf(funcs[name])
+		if t == nil || t.Kind() != reflect.Func {
+			continue
+		}
+		ref = append(ref, FuncDoc{
+			Name:      name,
+			Signature: funcSignature(name, t, 0),
+			Doc:       e.funcDoc(name, name, custom),
+		})
+
+		// Namespaces are funcs without arguments returning a pointer to a type named like
+		// HumanizeNamespace, their methods are called as `ns.Method`
+		if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Pointer || !strings.HasSuffix(t.Out(0).Elem().Name(), "Namespace") {
+			continue
+		}
+		ns := t.Out(0)
+		for i := 0; i < ns.NumMethod(); i++ {
+			m := ns.Method(i)
+			ref = append(ref, FuncDoc{
+				Name:      name + "." + m.Name,
+				Signature: funcSignature(name+"."+m.Name, m.Type, 1),
+				Doc:       e.funcDoc(name+"."+m.Name, ns.Elem().Name()+"."+m.Name, custom),
+			})
+		}
+	}
+	return ref
+}
+
+// funcDoc get the doc of the function from Config.FuncDocs, or the generated docs of the engine functions
+func (e *ViewEngine) funcDoc(name, builtin string, custom bool) string {
+	if doc, ok := e.config.FuncDocs[name]; ok || custom {
+		return doc
+	}
+	return funcDocs[builtin]
+}
+
+// funcSignature format the signature of the func type, skipping the receiver arguments
+func funcSignature(name string, t reflect.Type, skip int) string {
+	in := make([]string, 0, t.NumIn())
+	for i := skip; i < t.NumIn(); i++ {
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			in = append(in, "..."+typeName(t.In(i).Elem()))
+		} else {
+			in = append(in, typeName(t.In(i)))
+		}
+	}
+	out := make([]string, 0, t.NumOut())
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, typeName(t.Out(i)))
+	}
+	sig := name + "(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+		return sig
+	case 1:
+		return sig + " " + out[0]
+	}
+	return sig + " (" + strings.Join(out, ", ") + ")"
+}
+
+func typeName(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "interface {}", "any")
+}
+
+// WriteFuncReference write the function reference as "markdown" or "json", see FuncReference
+func (e *ViewEngine) WriteFuncReference(w io.Writer, format string) error {
+	ref := e.FuncReference()
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ref)
+	case "markdown", "md":
+		var b strings.Builder
+		b.WriteString("# Template functions\n\n| Function | Signature | Description |\n| --- | --- | --- |\n")
+		for _, f := range ref {
+			fmt.Fprintf(&b, "| %v | `%v` | %v |\n", f.Name, f.Signature, strings.ReplaceAll(f.Doc, "|", `\|`))
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	return fmt.Errorf("ViewEngine func reference unknown format: %q", format)
+}
@@ -0,0 +1,58 @@
+package goview
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+type TestNamespace struct{}
+
+func (TestNamespace) Hello(name string) string { return "hello " + name }
+
+func TestViewEngine_FuncReference(t *testing.T) {
+	gv := New(Config{
+		Funcs: template.FuncMap{
+			"sub":  func(a, b int) int { return a - b },
+			"test": func() *TestNamespace { return &TestNamespace{} },
+		},
+		FuncDocs:  map[string]string{"sub": "sub subtract b from a", "test.Hello": "Hello greet"},
+		DenyFuncs: []string{"os.*"},
+	})
+	ref := make(map[string]FuncDoc)
+	for _, f := range gv.FuncReference() {
+		ref[f.Name] = f
+		// Generated docs must cover all engine functions, run go generate after adding one
+		if f.Doc == "" && f.Name != "test" {
+			t.Errorf("missing doc: %v", f.Name)
+		}
+	}
+	for _, v := range []FuncDoc{
+		{Name: "sub", Signature: "sub(int, int) int", Doc: "sub subtract b from a"},
+		{Name: "test.Hello", Signature: "test.Hello(string) string", Doc: "Hello greet"},
+		{Name: "partial", Signature: "partial(string, ...any) (template.HTML, error)", Doc: funcDocs["partial"]},
+		{Name: "humanize.Comma", Signature: "humanize.Comma(any) (string, error)", Doc: funcDocs["HumanizeNamespace.Comma"]},
+	} {
+		if ref[v.Name] != v {
+			t.Errorf("actual: %+v, expect: %+v", ref[v.Name], v)
+		}
+	}
+	if _, ok := ref["os.ReadFile"]; ok {
+		t.Errorf("denied functions must not be listed")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gv.WriteFuncReference(buf, "markdown"); err != nil || !strings.Contains(buf.String(), "| sub | `sub(int, int) int` | sub subtract b from a |\n") {
+		t.Errorf("markdown: %v, error: %v", buf.String(), err)
+	}
+	buf.Reset()
+	var out []FuncDoc
+	if err := gv.WriteFuncReference(buf, "json"); err != nil || json.Unmarshal(buf.Bytes(), &out) != nil || len(out) != len(ref) {
+		t.Errorf("json: %v, error: %v", buf.String(), err)
+	}
+	if err := gv.WriteFuncReference(buf, "html"); err == nil {
+		t.Errorf("expect unknown format error")
+	}
+}
@@ -0,0 +1,90 @@
+package goview
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// NewContext return a copy of ctx carrying the engine
+func NewContext(ctx context.Context, e *ViewEngine) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext get the engine stored by Middleware or NewContext
+func FromContext(ctx context.Context) (*ViewEngine, bool) {
+	e, ok := ctx.Value(contextKey{}).(*ViewEngine)
+	return e, ok && e != nil
+}
+
+// Middleware net/http middleware storing the engine in the request context,
+// for `goview.Handler()` and `goview.FromContext()`.
+func Middleware(e *ViewEngine) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), e)))
+		})
+	}
+}
+
+// Handler http.Handler rendering the template with the data returned by dataFn, dataFn may be nil.
+// It renders with the engine of the request context, see Middleware, or with the default instance
+// set by Use before Handler is called.
+func Handler(name string, dataFn func(*http.Request) any) http.Handler {
+	// Resolve the default instance once here, requests only read it
+	if instance == nil {
+		instance = Default()
+	}
+	fallback := instance
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, ok := FromContext(r.Context())
+		if !ok {
+			e = fallback
+		}
+		e.serve(w, r, name, dataFn)
+	})
+}
+
+// Handler http.Handler rendering the template with the data returned by dataFn, dataFn may be nil.
+func (e *ViewEngine) Handler(name string, dataFn func(*http.Request) any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.serve(w, r, name, dataFn)
+	})
+}
+
+func (e *ViewEngine) serve(w http.ResponseWriter, r *http.Request, name string, dataFn func(*http.Request) any) {
+	var data any
+	if dataFn != nil {
+		data = dataFn(r)
+	}
+	if err := e.renderBuffered(w, r, http.StatusOK, name, data, e.requestOptions(r, nil)...); err != nil {
+		e.logger.Error("goview: handler render failed", "template", name, "path", r.URL.Path, "error", err)
+		if e.config.Debug {
+			if err := e.WriteErrorPage(w, r, name, data, err); err == nil {
+				return
+			}
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// RequestOptions the render options carried by the request: the request itself, the CSP nonce,
+// and the language matching Accept-Language when the engine has catalogs.
+// Framework adapters pass them to RenderWriter so request renders behave like Handler.
+func (e *ViewEngine) RequestOptions(r *http.Request) []RenderOption {
+	opts := make([]RenderOption, 0, 3)
+	opts = append(opts, WithRequest(r))
+	if nonce := CSPNonceFromContext(r.Context()); nonce != "" {
+		opts = append(opts, WithCSPNonce(nonce))
+	}
+	if e.i18n != nil && r.Header.Get("Accept-Language") != "" {
+		opts = append(opts, WithLanguage(e.RequestLanguage(r)))
+	}
+	return opts
+}
+
+// requestOptions prepend the render options carried by the request to opts
+func (e *ViewEngine) requestOptions(r *http.Request, opts []RenderOption) []RenderOption {
+	return append(e.RequestOptions(r), opts...)
+}
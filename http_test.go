@@ -0,0 +1,90 @@
+package goview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	handler := Middleware(gv)(Handler("sum", func(r *http.Request) any {
+		return M{
+			"sum": func(a int, b int) int {
+				return a + b
+			},
+			"a": 1,
+			"b": 2,
+		}
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertRecorder(t, recorder, http.StatusOK, "<v>3</v>")
+
+	recorder = httptest.NewRecorder()
+	gv.Handler("notfound", nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertRecorder(t, recorder, http.StatusInternalServerError, "Internal Server Error\n")
+}
+
+func TestHandler_DefaultInstance(t *testing.T) {
+	old := instance
+	defer Use(old)
+	Use(New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	}))
+	handler := Handler("sum", func(r *http.Request) any {
+		return M{"sum": func(a, b int) int { return a + b }, "a": 1, "b": 2}
+	})
+
+	// Requests without Middleware share the instance resolved by Handler
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			assertRecorder(t, recorder, http.StatusOK, "<v>3</v>")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFromContext(t *testing.T) {
+	gv := Default()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := FromContext(req.Context()); ok {
+		t.Error("engine found in empty context?")
+	}
+	if e, ok := FromContext(NewContext(req.Context(), gv)); !ok || e != gv {
+		t.Errorf("actual: %v, expect: %v", e, gv)
+	}
+}
+
+func TestViewEngine_RequestOptions(t *testing.T) {
+	i18n := NewI18n("en")
+	if err := i18n.LoadDir("_examples/test/i18n"); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	gv := New(Config{Root: "_examples/test", Extension: ".tpl"})
+	gv.SetI18n(i18n)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+	req = req.WithContext(ContextWithCSPNonce(req.Context(), "abc"))
+	renderCtx := &RenderContext{}
+	for _, opt := range gv.RequestOptions(req) {
+		opt(renderCtx)
+	}
+	if renderCtx.Request != req || renderCtx.CSPNonce != "abc" || renderCtx.Language != "de" {
+		t.Errorf("request: %v, nonce: %v, language: %v", renderCtx.Request != nil, renderCtx.CSPNonce, renderCtx.Language)
+	}
+}
@@ -0,0 +1,114 @@
+package goview
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// HumanizeNamespace `humanize` template namespace, formatting numbers for humans in English
+type HumanizeNamespace struct{}
+
+var humanizeNamespace = &HumanizeNamespace{}
+
+// Comma format the number with comma grouping separators, such as "1,234,567"
+func (*HumanizeNamespace) Comma(n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if hasFrac {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String(), nil
+}
+
+// Ordinal format the integer with its English ordinal suffix, such as "3rd"
+func (*HumanizeNamespace) Ordinal(n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	i := int64(f)
+	suffix := "th"
+	abs := i % 100
+	if abs < 0 {
+		abs = -abs
+	}
+	// 11th, 12th and 13th
+	if abs/10 != 1 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.FormatInt(i, 10) + suffix, nil
+}
+
+// Bytes format the byte size with SI units, such as "1.5 MB"
+func (*HumanizeNamespace) Bytes(n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	return formatBytes(f, 1000, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}), nil
+}
+
+// IBytes format the byte size with IEC units, such as "1.5 MiB"
+func (*HumanizeNamespace) IBytes(n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	return formatBytes(f, 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}), nil
+}
+
+// SIPrefix format the number with an SI prefix, such as "1.2M"
+func (*HumanizeNamespace) SIPrefix(n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	prefixes := []string{"", "k", "M", "G", "T", "P", "E"}
+	i := 0
+	for math.Abs(f) >= 999.95 && i < len(prefixes)-1 {
+		f /= 1000
+		i++
+	}
+	return strconv.FormatFloat(math.Round(f*10)/10, 'f', -1, 64) + prefixes[i], nil
+}
+
+// formatBytes format the size in the largest unit below it, with one decimal below 10
+func formatBytes(size, base float64, units []string) string {
+	if math.Abs(size) < base {
+		return strconv.FormatFloat(size, 'f', 0, 64) + " " + units[0]
+	}
+	i := 0
+	for math.Abs(size) >= base && i < len(units)-1 {
+		size /= base
+		i++
+	}
+	if math.Abs(size) < 10 {
+		return strconv.FormatFloat(size, 'f', 1, 64) + " " + units[i]
+	}
+	return strconv.FormatFloat(size, 'f', 0, 64) + " " + units[i]
+}
@@ -0,0 +1,60 @@
+package goview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHumanizeNamespace(t *testing.T) {
+	h := humanizeNamespace
+	for _, v := range []struct {
+		Name   string
+		Fn     func(any) (string, error)
+		In     any
+		Expect string
+	}{
+		{Name: "Comma", Fn: h.Comma, In: 1234567, Expect: "1,234,567"},
+		{Name: "Comma", Fn: h.Comma, In: -1234.5, Expect: "-1,234.5"},
+		{Name: "Comma", Fn: h.Comma, In: 123, Expect: "123"},
+		{Name: "Ordinal", Fn: h.Ordinal, In: 1, Expect: "1st"},
+		{Name: "Ordinal", Fn: h.Ordinal, In: 22, Expect: "22nd"},
+		{Name: "Ordinal", Fn: h.Ordinal, In: 13, Expect: "13th"},
+		{Name: "Ordinal", Fn: h.Ordinal, In: 111, Expect: "111th"},
+		{Name: "Ordinal", Fn: h.Ordinal, In: 103, Expect: "103rd"},
+		{Name: "Bytes", Fn: h.Bytes, In: 512, Expect: "512 B"},
+		{Name: "Bytes", Fn: h.Bytes, In: 1536000, Expect: "1.5 MB"},
+		{Name: "Bytes", Fn: h.Bytes, In: 82854982, Expect: "83 MB"},
+		{Name: "IBytes", Fn: h.IBytes, In: 1536, Expect: "1.5 KiB"},
+		{Name: "SIPrefix", Fn: h.SIPrefix, In: 1234567, Expect: "1.2M"},
+		{Name: "SIPrefix", Fn: h.SIPrefix, In: 999, Expect: "999"},
+		{Name: "SIPrefix", Fn: h.SIPrefix, In: 999999, Expect: "1M"},
+		{Name: "SIPrefix", Fn: h.SIPrefix, In: -2500, Expect: "-2.5k"},
+	} {
+		val, err := v.Fn(v.In)
+		if err != nil {
+			t.Fatalf("%v(%v) error: %v", v.Name, v.In, err)
+		}
+		if val != v.Expect {
+			t.Errorf("%v(%v) actual: %v, expect: %v", v.Name, v.In, val, v.Expect)
+		}
+	}
+	if _, err := h.Comma("abc"); err == nil {
+		t.Error("comma of string is ok?")
+	}
+}
+
+func TestViewEngine_Humanize(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "humanize", M{"n": 1234567}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := "<v>1,234,567 3rd 1.5 MB 1.2M</v>"
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
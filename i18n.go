@@ -0,0 +1,492 @@
+package goview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// I18n translation message catalogs per language.
+//
+// Catalog files are named by language, such as `en.json` or `de-AT.json`, and map message keys
+// to messages. Nested objects are flattened into dotted keys, and an object with plural forms
+// (`zero`, `one`, `two`, `few`, `many`, `other`) is selected by the `count` argument with the CLDR
+// plural rules of the catalog language, `zero` is also used for 0. Placeholders such as `{name}`
+// are replaced by the arguments.
+//
+//	{"greeting": "Hello {name}!", "items": {"one": "{count} item", "other": "{count} items"}}
+type I18n struct {
+	defaultLanguage string
+
+	mu         sync.RWMutex
+	catalogs   map[string]map[string]i18nMessage
+	unmarshals map[string]func(data []byte, v any) error
+	fallbackTo map[string][]string
+}
+
+// i18nMessage translation message, plural forms are empty for simple messages
+type i18nMessage struct {
+	text   string
+	plural map[string]string
+}
+
+var pluralForms = []string{"zero", "one", "two", "few", "many", "other"}
+
+// pluralFormNames names of the CLDR plural forms
+var pluralFormNames = map[plural.Form]string{
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+	plural.Other: "other",
+}
+
+// NewI18n new empty catalogs, missing messages fall back to the default language.
+// JSON, TOML and YAML catalogs are supported, register more formats with RegisterFormat.
+func NewI18n(defaultLanguage string) *I18n {
+	return &I18n{
+		defaultLanguage: normalizeLanguage(defaultLanguage),
+		catalogs:        make(map[string]map[string]i18nMessage),
+		unmarshals: map[string]func(data []byte, v any) error{
+			".json": json.Unmarshal,
+			".toml": toml.Unmarshal,
+			".yaml": yaml.Unmarshal,
+			".yml":  yaml.Unmarshal,
+		},
+		fallbackTo: make(map[string][]string),
+	}
+}
+
+// SetFallbacks set the languages to look up messages missing in the language, before its
+// base language and the default language, such as `SetFallbacks("de-CH", "de-DE", "fr")`.
+func (i *I18n) SetFallbacks(language string, fallbacks ...string) {
+	normalized := make([]string, 0, len(fallbacks))
+	for _, v := range fallbacks {
+		normalized = append(normalized, normalizeLanguage(v))
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.fallbackTo[normalizeLanguage(language)] = normalized
+}
+
+// DefaultLanguage get the default language
+func (i *I18n) DefaultLanguage() string {
+	return i.defaultLanguage
+}
+
+// RegisterFormat register an unmarshal function for catalog files with the extension, such as
+// `RegisterFormat(".ini", ini.Unmarshal)`, replacing the builtin JSON, TOML and YAML formats.
+func (i *I18n) RegisterFormat(ext string, unmarshal func(data []byte, v any) error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.unmarshals[strings.ToLower(ext)] = unmarshal
+}
+
+// LoadDir load all catalog files of registered formats in the directory
+func (i *I18n) LoadDir(dir string) error {
+	return i.LoadFS(os.DirFS(dir), ".")
+}
+
+// LoadFS load all catalog files of registered formats in the directory of fsys, such as an embed.FS
+func (i *I18n) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("ViewEngine i18n read dir:%v, error: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		i.mu.RLock()
+		unmarshal, ok := i.unmarshals[ext]
+		i.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		file := path.Join(dir, entry.Name())
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("ViewEngine i18n read file:%v, error: %v", file, err)
+		}
+		messages := make(map[string]any)
+		if err := unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("ViewEngine i18n parse file:%v, error: %v", file, err)
+		}
+		if err := i.AddMessages(strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())), messages); err != nil {
+			return fmt.Errorf("ViewEngine i18n file:%v, error: %v", file, err)
+		}
+	}
+	return nil
+}
+
+// AddMessages add messages to the catalog of the language, replacing messages with the same key
+func (i *I18n) AddMessages(language string, messages map[string]any) error {
+	flat := make(map[string]i18nMessage)
+	if err := flattenMessages("", messages, flat); err != nil {
+		return err
+	}
+	language = normalizeLanguage(language)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	catalog, ok := i.catalogs[language]
+	if !ok {
+		catalog = make(map[string]i18nMessage)
+		i.catalogs[language] = catalog
+	}
+	for k, v := range flat {
+		catalog[k] = v
+	}
+	return nil
+}
+
+func flattenMessages(prefix string, messages map[string]any, out map[string]i18nMessage) error {
+	for k, v := range messages {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case string:
+			out[key] = i18nMessage{text: val}
+		case map[string]any:
+			if plural, ok := pluralMessage(val); ok {
+				out[key] = plural
+				continue
+			}
+			if err := flattenMessages(key, val, out); err != nil {
+				return err
+			}
+		default:
+			// YAML decoders produce map[any]any and other map types
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Map {
+				return fmt.Errorf("invalid message key:%v, value: %v", key, v)
+			}
+			nested := make(map[string]any, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				nested[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+			}
+			if err := flattenMessages(prefix, map[string]any{k: nested}, out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pluralMessage convert an object with only plural form keys and string values
+func pluralMessage(v map[string]any) (i18nMessage, bool) {
+	if _, ok := v["other"]; !ok {
+		return i18nMessage{}, false
+	}
+	plural := make(map[string]string, len(v))
+	for k, form := range v {
+		text, ok := form.(string)
+		if !ok || !isPluralForm(k) {
+			return i18nMessage{}, false
+		}
+		plural[k] = text
+	}
+	return i18nMessage{plural: plural}, true
+}
+
+func isPluralForm(form string) bool {
+	for _, v := range pluralForms {
+		if v == form {
+			return true
+		}
+	}
+	return false
+}
+
+// Languages get the languages with catalogs, sorted
+func (i *I18n) Languages() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	languages := make([]string, 0, len(i.catalogs))
+	for k := range i.catalogs {
+		languages = append(languages, k)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Translate translate the message key for the language, falling back to the base language
+// (`de` for `de-AT`) and the default language. The key is returned when no catalog has it.
+// Arguments are name/value pairs or a single map, replacing `{name}` placeholders.
+func (i *I18n) Translate(language, key string, args ...any) (string, error) {
+	params, err := messageParams(args)
+	if err != nil {
+		return "", fmt.Errorf("translate key:%v, error: %v", key, err)
+	}
+	msg, lang, ok := i.lookup(language, key)
+	if !ok {
+		return key, nil
+	}
+	text := msg.text
+	if msg.plural != nil {
+		text = msg.plural[pluralForm(lang, params["count"], msg.plural)]
+	}
+	return replaceParams(text, params), nil
+}
+
+// lookup find the message and the language of its catalog in the language fallback chain
+func (i *I18n) lookup(language, key string) (i18nMessage, string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, lang := range i.fallbacks(language) {
+		if msg, ok := i.catalogs[lang][key]; ok {
+			return msg, lang, true
+		}
+	}
+	return i18nMessage{}, "", false
+}
+
+// fallbacks get the languages to look up messages in, most specific first:
+// the language, its configured fallbacks, its base languages and the default language
+func (i *I18n) fallbacks(language string) []string {
+	language = normalizeLanguage(language)
+	chain := make([]string, 0, 4)
+	add := func(lang string) {
+		for _, v := range chain {
+			if v == lang {
+				return
+			}
+		}
+		chain = append(chain, lang)
+	}
+	if language != "" {
+		add(language)
+	}
+	for _, lang := range i.fallbackTo[language] {
+		add(lang)
+	}
+	for lang := language; strings.Contains(lang, "-"); {
+		lang = lang[:strings.LastIndex(lang, "-")]
+		add(lang)
+	}
+	if i.defaultLanguage != "" {
+		add(i.defaultLanguage)
+	}
+	return chain
+}
+
+// MatchLanguage pick the language with a catalog best matching the Accept-Language header,
+// by q-value and then header order. A tag matches its own catalog, its base language catalog
+// (`de` for `de-AT`) or a regional catalog of it (`en-us` for `en`). The default language is
+// returned when nothing matches.
+func (i *I18n) MatchLanguage(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	tags := make([]weighted, 0)
+	for _, spec := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(spec), ";")
+		tag = normalizeLanguage(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(a, b int) bool {
+		return tags[a].q > tags[b].q
+	})
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, v := range tags {
+		if v.tag == "*" {
+			return i.defaultLanguage
+		}
+		if _, ok := i.catalogs[v.tag]; ok {
+			return v.tag
+		}
+		for lang := v.tag; strings.Contains(lang, "-"); {
+			lang = lang[:strings.LastIndex(lang, "-")]
+			if _, ok := i.catalogs[lang]; ok {
+				return lang
+			}
+		}
+		languages := make([]string, 0, len(i.catalogs))
+		for lang := range i.catalogs {
+			languages = append(languages, lang)
+		}
+		sort.Strings(languages)
+		for _, lang := range languages {
+			if strings.HasPrefix(lang, v.tag+"-") {
+				return lang
+			}
+		}
+	}
+	return i.defaultLanguage
+}
+
+// RequestLanguage pick the language of the catalogs best matching the request Accept-Language
+// header, empty if the engine has no catalogs
+func (e *ViewEngine) RequestLanguage(r *http.Request) string {
+	if e.i18n == nil {
+		return ""
+	}
+	return e.i18n.MatchLanguage(r.Header.Get("Accept-Language"))
+}
+
+// normalizeLanguage normalize language tags such as `en_US` to `en-us`
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
+
+func messageParams(args []any) (map[string]any, error) {
+	params := make(map[string]any)
+	if len(args) == 1 {
+		rv := reflect.ValueOf(args[0])
+		if rv.Kind() != reflect.Map {
+			return nil, fmt.Errorf("argument must be a map or name/value pairs")
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			params[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		return params, nil
+	}
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("odd number of name/value arguments")
+	}
+	for idx := 0; idx < len(args); idx += 2 {
+		name, ok := args[idx].(string)
+		if !ok {
+			return nil, fmt.Errorf("argument name must be a string: %v", args[idx])
+		}
+		params[name] = args[idx+1]
+	}
+	return params, nil
+}
+
+// pluralForm select the plural form of the language by count with the CLDR plural rules, zero when
+// present for 0, other when the form is missing
+func pluralForm(lang string, count any, forms map[string]string) string {
+	n, err := strconv.ParseFloat(fmt.Sprint(count), 64)
+	if err != nil {
+		return "other"
+	}
+	if _, ok := forms["zero"]; ok && n == 0 {
+		return "zero"
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		tag = language.English
+	}
+
+	// Plural operands of the decimal count: integer digits, visible fraction digits with and
+	// without trailing zeros, such as "1.50" for i=1 v=2 w=1 f=50 t=5
+	digits := strings.TrimPrefix(fmt.Sprint(count), "-")
+	if strings.ContainsAny(digits, "eE") {
+		digits = strconv.FormatFloat(math.Abs(n), 'f', -1, 64)
+	}
+	intPart, frac, _ := strings.Cut(digits, ".")
+	trimmed := strings.TrimRight(frac, "0")
+	i, _ := strconv.Atoi(intPart)
+	f, _ := strconv.Atoi(frac)
+	t, _ := strconv.Atoi(trimmed)
+	form := pluralFormNames[plural.Cardinal.MatchPlural(tag, i, len(frac), len(trimmed), f, t)]
+	if _, ok := forms[form]; !ok {
+		return "other"
+	}
+	return form
+}
+
+func replaceParams(text string, params map[string]any) string {
+	if len(params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// SetI18n set the translation catalogs of the `lang` and `T` template functions
+func (e *ViewEngine) SetI18n(i18n *I18n) {
+	e.i18n = i18n
+}
+
+// I18n get the translation catalogs of the engine, nil if not set
+func (e *ViewEngine) I18n() *I18n {
+	return e.i18n
+}
+
+// WithLanguage set the language of the render, the default language of the catalogs is used otherwise
+func WithLanguage(language string) RenderOption {
+	return func(ctx *RenderContext) {
+		ctx.Language = language
+	}
+}
+
+// LangNamespace `lang` template namespace, such as `{{lang.Translate "greeting" "name" .Name}}`
+type LangNamespace struct {
+	i18n     *I18n
+	language string
+}
+
+// Language get the language of the render
+func (ns *LangNamespace) Language() string {
+	return ns.language
+}
+
+// Translate translate the message key, see I18n.Translate
+func (ns *LangNamespace) Translate(key string, args ...any) (string, error) {
+	if ns.i18n == nil {
+		return key, nil
+	}
+	return ns.i18n.Translate(ns.language, key, args...)
+}
+
+// Match pick the language of the catalogs best matching the Accept-Language header value,
+// see I18n.MatchLanguage
+func (ns *LangNamespace) Match(acceptLanguage string) string {
+	if ns.i18n == nil {
+		return ns.language
+	}
+	return ns.i18n.MatchLanguage(acceptLanguage)
+}
+
+// T alias of Translate
+func (ns *LangNamespace) T(key string, args ...any) (string, error) {
+	return ns.Translate(key, args...)
+}
+
+// langNamespace get the `lang` namespace of the render
+func (e *ViewEngine) langNamespace(renderCtx *RenderContext) *LangNamespace {
+	language := renderCtx.Language
+	if language == "" && e.i18n != nil {
+		language = e.i18n.DefaultLanguage()
+	}
+	return &LangNamespace{i18n: e.i18n, language: language}
+}
@@ -0,0 +1,138 @@
+package goview
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// FormatNumber format the number with precision decimals, using the decimal and grouping
+// separators of the render language, such as `1,234.56` for en and `1.234,56` for de.
+func (ns *LangNamespace) FormatNumber(precision int, n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	return ns.printer().Sprint(number.Decimal(f, number.Precision(-1), number.Scale(precision))), nil
+}
+
+// FormatPercent format the percent value with precision decimals for the render language,
+// such as `33.33%` for en and `33,33 %` for de. The value is in percent, 33.33 means 33.33%.
+func (ns *LangNamespace) FormatPercent(precision int, n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	return ns.printer().Sprint(number.Percent(f/100, number.Precision(-1), number.Scale(precision))), nil
+}
+
+// FormatCurrency format the amount in the currency, an ISO 4217 code such as "EUR", with the
+// currency digits, symbol and number format of the render language from the CLDR data of
+// golang.org/x/text, such as `€ 19.99` for en and `€ 19,99` for de.
+func (ns *LangNamespace) FormatCurrency(n any, code string) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("invalid currency: %q", code)
+	}
+	return ns.printer().Sprint(currency.Symbol(unit.Amount(f))), nil
+}
+
+// FormatNumberCustom format the number with precision decimals and custom separators,
+// options are the minus sign, decimal point and grouping separator separated by spaces,
+// `- . ,` by default. An empty grouping separator disables grouping.
+func (ns *LangNamespace) FormatNumberCustom(precision int, n any, options ...string) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	minus, decimal, group := "-", ".", ","
+	if len(options) > 0 {
+		opts := strings.Split(options[0], " ")
+		if len(opts) < 2 || len(opts) > 3 {
+			return "", fmt.Errorf("invalid number format options: %q", options[0])
+		}
+		minus, decimal, group = opts[0], opts[1], ""
+		if len(opts) == 3 {
+			group = opts[2]
+		}
+	}
+
+	s := strconv.FormatFloat(math.Abs(f), 'f', max(precision, 0), 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	var out strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		out.WriteString(minus)
+	}
+	for i, r := range intPart {
+		if i > 0 && group != "" && (len(intPart)-i)%3 == 0 {
+			out.WriteString(group)
+		}
+		out.WriteRune(r)
+	}
+	if fracPart != "" {
+		out.WriteString(decimal)
+		out.WriteString(fracPart)
+	}
+	return out.String(), nil
+}
+
+// printer get the message printer of the render language
+func (ns *LangNamespace) printer() *message.Printer {
+	return message.NewPrinter(languageTag(ns.language))
+}
+
+// languageTag parse the language, falling back to English for unknown languages
+func languageTag(lang string) language.Tag {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return language.English
+	}
+	return tag
+}
+
+// toFloat convert numbers and numeric strings to float64
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int8:
+		return float64(n), nil
+	case int16:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint8:
+		return float64(n), nil
+	case uint16:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number: %q", n)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("invalid number: %v", v)
+}
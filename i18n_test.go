@@ -0,0 +1,203 @@
+package goview
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestI18n_Translate(t *testing.T) {
+	i18n := NewI18n("en")
+	if err := i18n.LoadDir("_examples/test/i18n"); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	for _, v := range []struct {
+		Language string
+		Key      string
+		Args     []any
+		Out      string
+	}{
+		{Language: "en", Key: "greeting", Args: []any{"name", "GoView"}, Out: "Hello GoView!"},
+		{Language: "de", Key: "greeting", Args: []any{M{"name": "GoView"}}, Out: "Hallo GoView!"},
+		{Language: "de_AT", Key: "greeting", Args: []any{"name", "GoView"}, Out: "Hallo GoView!"},
+		{Language: "de", Key: "nav.home", Out: "Home"},
+		{Language: "fr", Key: "greeting", Args: []any{"name", "GoView"}, Out: "Hello GoView!"},
+		{Language: "en", Key: "items", Args: []any{"count", 0}, Out: "No items"},
+		{Language: "en", Key: "items", Args: []any{"count", 1}, Out: "1 item"},
+		{Language: "de", Key: "items", Args: []any{"count", 0}, Out: "0 Einträge"},
+		{Language: "de", Key: "items", Args: []any{"count", 2}, Out: "2 Einträge"},
+		{Language: "en", Key: "missing", Out: "missing"},
+		{Language: "it", Key: "greeting", Args: []any{"name", "GoView"}, Out: "Ciao GoView!"},
+		{Language: "it", Key: "items", Args: []any{"count", 2}, Out: "2 elementi"},
+		{Language: "es", Key: "nav.home", Out: "Inicio"},
+		{Language: "es", Key: "items", Args: []any{"count", 1}, Out: "1 elemento"},
+	} {
+		val, err := i18n.Translate(v.Language, v.Key, v.Args...)
+		if err != nil {
+			t.Errorf("language: %v, key: %v, error: %v", v.Language, v.Key, err)
+			continue
+		}
+		if val != v.Out {
+			t.Errorf("language: %v, key: %v, actual: %v, expect: %v", v.Language, v.Key, val, v.Out)
+		}
+	}
+
+	// CLDR plural rules of the catalog language
+	if err := i18n.AddMessages("pl", map[string]any{
+		"files": map[string]any{"one": "{count} plik", "few": "{count} pliki", "many": "{count} plików", "other": "{count} pliku"},
+	}); err != nil {
+		t.Fatalf("add messages error: %v", err)
+	}
+	for count, out := range map[any]string{1: "1 plik", 3: "3 pliki", 22: "22 pliki", 5: "5 plików", 12: "12 plików", "1.5": "1.5 pliku"} {
+		if val, _ := i18n.Translate("pl", "files", "count", count); val != out {
+			t.Errorf("count: %v, actual: %v, expect: %v", count, val, out)
+		}
+	}
+	if val, _ := i18n.Translate("en", "items", "count", "1.0"); val != "1.0 items" {
+		t.Errorf("count: 1.0, actual: %v", val)
+	}
+
+	if _, err := i18n.Translate("en", "greeting", "name"); err == nil {
+		t.Error("translate with odd arguments is ok?")
+	}
+}
+
+func TestViewEngine_Lang(t *testing.T) {
+	i18n := NewI18n("en")
+	if err := i18n.LoadDir("_examples/test/i18n"); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	gv.SetI18n(i18n)
+
+	for _, v := range []struct {
+		Opts []RenderOption
+		Out  string
+	}{
+		{Out: "<v>en|Hello GoView!|Home|3 items|missing</v>"},
+		{Opts: []RenderOption{WithLanguage("de")}, Out: "<v>de|Hallo GoView!|Home|3 Einträge|missing</v>"},
+	} {
+		buff := new(bytes.Buffer)
+		if err := gv.RenderWriter(buff, "lang", M{"name": "GoView", "count": 3}, v.Opts...); err != nil {
+			t.Errorf("render error: %v", err)
+			continue
+		}
+		if val := buff.String(); val != v.Out {
+			t.Errorf("actual: %v, expect: %v", val, v.Out)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de-AT,de;q=0.9,en;q=0.8")
+	recorder := httptest.NewRecorder()
+	if err := gv.RenderRequest(recorder, req, http.StatusOK, "lang", M{"name": "GoView", "count": 3}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	assertRecorder(t, recorder, http.StatusOK, "<v>de|Hallo GoView!|Home|3 Einträge|missing</v>")
+}
+
+func TestLangNamespace_Format(t *testing.T) {
+	en := &LangNamespace{language: "en"}
+	de := &LangNamespace{language: "de"}
+	for _, v := range []struct {
+		Format func() (string, error)
+		Out    string
+	}{
+		{Format: func() (string, error) { return en.FormatNumber(2, 1234.567) }, Out: "1,234.57"},
+		{Format: func() (string, error) { return de.FormatNumber(2, 1234.567) }, Out: "1.234,57"},
+		{Format: func() (string, error) { return en.FormatNumber(0, "1234567") }, Out: "1,234,567"},
+		{Format: func() (string, error) { return en.FormatPercent(1, 33.333) }, Out: "33.3%"},
+		{Format: func() (string, error) { return de.FormatPercent(1, 33.333) }, Out: "33,3 %"},
+		{Format: func() (string, error) { return en.FormatNumberCustom(2, -1234567.891) }, Out: "-1,234,567.89"},
+		{Format: func() (string, error) { return en.FormatNumberCustom(1, 1234567.891, "- , .") }, Out: "1.234.567,9"},
+		{Format: func() (string, error) { return en.FormatNumberCustom(0, 1234, "- .") }, Out: "1234"},
+	} {
+		val, err := v.Format()
+		if err != nil {
+			t.Errorf("format error: %v", err)
+			continue
+		}
+		if val != v.Out {
+			t.Errorf("actual: %q, expect: %q", val, v.Out)
+		}
+	}
+
+	if _, err := en.FormatNumber(2, "abc"); err == nil {
+		t.Error("format invalid number is ok?")
+	}
+}
+
+func TestLangNamespace_FormatCurrency(t *testing.T) {
+	for _, v := range []struct {
+		Language string
+		Amount   any
+		Currency string
+		Out      string
+	}{
+		{Language: "en", Amount: 19.99, Currency: "EUR", Out: "€ 19.99"},
+		{Language: "en", Amount: -1234.5, Currency: "USD", Out: "$ -1,234.50"},
+		{Language: "de", Amount: 19.99, Currency: "EUR", Out: "€ 19,99"},
+		{Language: "de-CH", Amount: 19.99, Currency: "CHF", Out: "CHF 19.99"},
+		{Language: "en", Amount: 19.99, Currency: "CHF", Out: "CHF 19.99"},
+		{Language: "ja", Amount: 1234, Currency: "JPY", Out: "￥ 1,234"},
+		{Language: "ja", Amount: 19.99, Currency: "JPY", Out: "￥ 20"},
+		{Language: "nl", Amount: "19.99", Currency: "EUR", Out: "€ 19,99"},
+		{Language: "sw", Amount: -0.001, Currency: "EUR", Out: "€ 0.00"},
+	} {
+		ns := &LangNamespace{language: v.Language}
+		val, err := ns.FormatCurrency(v.Amount, v.Currency)
+		if err != nil {
+			t.Errorf("format error: %v", err)
+			continue
+		}
+		if val != v.Out {
+			t.Errorf("language: %v, actual: %q, expect: %q", v.Language, val, v.Out)
+		}
+	}
+
+	if _, err := (&LangNamespace{language: "en"}).FormatCurrency(1, "XYZ1"); err == nil {
+		t.Error("format invalid currency is ok?")
+	}
+}
+
+func TestI18n_MatchLanguage(t *testing.T) {
+	i18n := NewI18n("en")
+	for _, lang := range []string{"en", "de", "fr-CA"} {
+		if err := i18n.AddMessages(lang, map[string]any{"lang": lang}); err != nil {
+			t.Fatalf("add messages error: %v", err)
+		}
+	}
+	for accept, expect := range map[string]string{
+		"":                         "en",
+		"de":                       "de",
+		"de-AT,de;q=0.9,en;q=0.8":  "de",
+		"it, fr;q=0.9":             "fr-ca",
+		"en;q=0.5, de;q=0.8":       "de",
+		"ja, *;q=0.1":              "en",
+		"de;q=0, es":               "en",
+		"fr-CA;q=0.7, DE_ch;q=0.7": "fr-ca",
+	} {
+		if val := i18n.MatchLanguage(accept); val != expect {
+			t.Errorf("accept: %v, actual: %v, expect: %v", accept, val, expect)
+		}
+	}
+}
+
+func TestI18n_SetFallbacks(t *testing.T) {
+	i18n := NewI18n("en")
+	i18n.AddMessages("en", map[string]any{"a": "en-a", "b": "en-b", "c": "en-c"})
+	i18n.AddMessages("fr", map[string]any{"a": "fr-a", "b": "fr-b"})
+	i18n.AddMessages("de", map[string]any{"a": "de-a"})
+	i18n.SetFallbacks("de-CH", "fr")
+
+	for key, expect := range map[string]string{"a": "fr-a", "b": "fr-b", "c": "en-c"} {
+		if val, _ := i18n.Translate("de-CH", key); val != expect {
+			t.Errorf("key: %v, actual: %v, expect: %v", key, val, expect)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package goview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+)
+
+// ImageConfig dimensions and format of an image
+type ImageConfig struct {
+	Width  int
+	Height int
+	Format string //such as "jpeg", "png" or "gif"
+}
+
+// ImageEXIF capture metadata of a JPEG image
+type ImageEXIF struct {
+	Date time.Time      //DateTimeOriginal, or DateTime of the file
+	Lat  float64        //GPS latitude in degrees, 0 if not recorded
+	Long float64        //GPS longitude in degrees, 0 if not recorded
+	Tags map[string]any //known tags by name, such as "Model", "FNumber" or "ISOSpeedRatings"
+}
+
+// exifTags names of the read EXIF tags by IFD and tag number
+var exifTags = map[uint16]string{
+	0x010F: "Make",
+	0x0110: "Model",
+	0x0112: "Orientation",
+	0x0131: "Software",
+	0x0132: "DateTime",
+	0x013B: "Artist",
+	0x8298: "Copyright",
+	0x829A: "ExposureTime",
+	0x829D: "FNumber",
+	0x8827: "ISOSpeedRatings",
+	0x9003: "DateTimeOriginal",
+	0x9004: "DateTimeDigitized",
+	0x920A: "FocalLength",
+	0xA434: "LensModel",
+}
+
+var gpsTags = map[uint16]string{
+	0x0001: "GPSLatitudeRef",
+	0x0002: "GPSLatitude",
+	0x0003: "GPSLongitudeRef",
+	0x0004: "GPSLongitude",
+	0x0006: "GPSAltitude",
+}
+
+const (
+	exifIFDPointer = 0x8769
+	gpsIFDPointer  = 0x8825
+	exifDateLayout = "2006:01:02 15:04:05"
+)
+
+var errInvalidEXIF = errors.New("invalid exif data")
+
+// ImageConfig get the dimensions and format of the image resource
+func (rs *Resources) ImageConfig(r *Resource) (ImageConfig, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(r.content))
+	if err != nil {
+		return ImageConfig{}, fmt.Errorf("ViewEngine image config name:%v, error: %v", r.name, err)
+	}
+	return ImageConfig{Width: cfg.Width, Height: cfg.Height, Format: format}, nil
+}
+
+// EXIF get the EXIF metadata of the JPEG image resource, nil if it has none
+func (rs *Resources) EXIF(r *Resource) (*ImageEXIF, error) {
+	data := findEXIF(r.content)
+	if data == nil {
+		return nil, nil
+	}
+	x, err := parseEXIF(data)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine image exif name:%v, error: %v", r.name, err)
+	}
+	return x, nil
+}
+
+// findEXIF get the TIFF data of the JPEG APP1 Exif segment
+func findEXIF(content []byte) []byte {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return nil
+	}
+	for i := 2; i+4 <= len(content) && content[i] == 0xFF; {
+		marker := content[i+1]
+		// Start of scan, no metadata follows
+		if marker == 0xDA {
+			return nil
+		}
+		size := int(binary.BigEndian.Uint16(content[i+2:]))
+		end := i + 2 + size
+		if size < 2 || end > len(content) {
+			return nil
+		}
+		if segment := content[i+4 : end]; marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		i = end
+	}
+	return nil
+}
+
+func parseEXIF(data []byte) (*ImageEXIF, error) {
+	if len(data) < 8 {
+		return nil, errInvalidEXIF
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, errInvalidEXIF
+	}
+	if order.Uint16(data[2:]) != 42 {
+		return nil, errInvalidEXIF
+	}
+
+	x := &ImageEXIF{Tags: make(map[string]any)}
+	pointers, err := readIFD(data, order, order.Uint32(data[4:]), exifTags, x.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if off, ok := pointers[exifIFDPointer]; ok {
+		if _, err := readIFD(data, order, off, exifTags, x.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if off, ok := pointers[gpsIFDPointer]; ok {
+		if _, err := readIFD(data, order, off, gpsTags, x.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tag := range []string{"DateTimeOriginal", "DateTime"} {
+		if s, ok := x.Tags[tag].(string); ok {
+			if t, err := time.Parse(exifDateLayout, s); err == nil {
+				x.Date = t
+				break
+			}
+		}
+	}
+	x.Lat = gpsDegrees(x.Tags["GPSLatitude"], x.Tags["GPSLatitudeRef"], "S")
+	x.Long = gpsDegrees(x.Tags["GPSLongitude"], x.Tags["GPSLongitudeRef"], "W")
+	return x, nil
+}
+
+// readIFD read the known tags of the IFD at the offset into tags, and return the IFD pointers
+func readIFD(data []byte, order binary.ByteOrder, off uint32, names map[uint16]string, tags map[string]any) (map[uint16]uint32, error) {
+	if uint64(off)+2 > uint64(len(data)) {
+		return nil, errInvalidEXIF
+	}
+	count := int(order.Uint16(data[off:]))
+	start := int(off) + 2
+	if start+count*12 > len(data) {
+		return nil, errInvalidEXIF
+	}
+	pointers := make(map[uint16]uint32)
+	for i := 0; i < count; i++ {
+		entry := data[start+i*12 : start+i*12+12]
+		tag, typ, n := order.Uint16(entry), order.Uint16(entry[2:]), order.Uint32(entry[4:])
+		if tag == exifIFDPointer || tag == gpsIFDPointer {
+			pointers[tag] = order.Uint32(entry[8:])
+			continue
+		}
+		name, ok := names[tag]
+		if !ok {
+			continue
+		}
+		if v, ok := exifValue(data, order, entry, typ, n); ok {
+			tags[name] = v
+		}
+	}
+	return pointers, nil
+}
+
+// exifValue decode the ASCII, SHORT, LONG and RATIONAL value of the IFD entry,
+// a single number or a slice of numbers
+func exifValue(data []byte, order binary.ByteOrder, entry []byte, typ uint16, n uint32) (any, bool) {
+	sizes := map[uint16]uint32{2: 1, 3: 2, 4: 4, 5: 8}
+	size, ok := sizes[typ]
+	if !ok || n == 0 || n > 1<<16 {
+		return nil, false
+	}
+	raw := entry[8:12]
+	if total := size * n; total > 4 {
+		off := order.Uint32(entry[8:])
+		if uint64(off)+uint64(total) > uint64(len(data)) {
+			return nil, false
+		}
+		raw = data[off : off+total]
+	}
+
+	if typ == 2 {
+		return strings.TrimRight(string(raw[:n]), "\x00 "), true
+	}
+	values := make([]any, n)
+	for i := range values {
+		switch typ {
+		case 3:
+			values[i] = int(order.Uint16(raw[i*2:]))
+		case 4:
+			values[i] = int(order.Uint32(raw[i*4:]))
+		case 5:
+			num, den := order.Uint32(raw[i*8:]), order.Uint32(raw[i*8+4:])
+			if den == 0 {
+				return nil, false
+			}
+			values[i] = float64(num) / float64(den)
+		}
+	}
+	if n == 1 {
+		return values[0], true
+	}
+	return values, true
+}
+
+// gpsDegrees convert the degrees, minutes and seconds to degrees, negative for the ref
+func gpsDegrees(dms, ref any, negativeRef string) float64 {
+	parts, ok := dms.([]any)
+	if !ok || len(parts) != 3 {
+		return 0
+	}
+	var deg float64
+	for i, div := range []float64{1, 60, 3600} {
+		f, _ := parts[i].(float64)
+		deg += f / div
+	}
+	if ref == negativeRef {
+		deg = -deg
+	}
+	return deg
+}
+
+// Config get the dimensions and format of the image, see Resources.ImageConfig
+func (n *ImagesNamespace) Config(img any) (ImageConfig, error) {
+	rs, r, err := n.resource(img)
+	if err != nil {
+		return ImageConfig{}, err
+	}
+	return rs.ImageConfig(r)
+}
+
+// EXIF get the EXIF metadata of the JPEG image, nil if it has none, see Resources.EXIF
+func (n *ImagesNamespace) EXIF(img any) (*ImageEXIF, error) {
+	rs, r, err := n.resource(img)
+	if err != nil {
+		return nil, err
+	}
+	return rs.EXIF(r)
+}
@@ -0,0 +1,160 @@
+package goview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"math"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+type testIFDEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+	ifd   int // index of the IFD the entry points to, for IFD pointers
+}
+
+// testTIFF lay out the little endian IFDs one after another, followed by the values larger than 4 bytes
+func testTIFF(ifds ...[]testIFDEntry) []byte {
+	le := binary.LittleEndian
+	offsets := make([]uint32, len(ifds))
+	off := uint32(8)
+	for i, entries := range ifds {
+		offsets[i] = off
+		off += 2 + 12*uint32(len(entries)) + 4
+	}
+	out := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	var values []byte
+	for _, entries := range ifds {
+		out = le.AppendUint16(out, uint16(len(entries)))
+		for _, e := range entries {
+			out = le.AppendUint16(out, e.tag)
+			out = le.AppendUint16(out, e.typ)
+			out = le.AppendUint32(out, e.count)
+			switch {
+			case e.data == nil:
+				out = le.AppendUint32(out, offsets[e.ifd])
+			case len(e.data) <= 4:
+				out = append(out, append(e.data, make([]byte, 4-len(e.data))...)...)
+			default:
+				out = le.AppendUint32(out, off+uint32(len(values)))
+				values = append(values, e.data...)
+			}
+		}
+		out = le.AppendUint32(out, 0)
+	}
+	return append(out, values...)
+}
+
+func testRationals(v ...uint32) []byte {
+	var out []byte
+	for i := 0; i < len(v); i += 2 {
+		out = binary.LittleEndian.AppendUint32(out, v[i])
+		out = binary.LittleEndian.AppendUint32(out, v[i+1])
+	}
+	return out
+}
+
+// testJPEG jpeg of the size, with the APP1 segment when exif is not nil
+func testJPEG(t *testing.T, w, h int, exif []byte) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, image.NewGray(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if exif == nil {
+		return buf.Bytes()
+	}
+	segment := append([]byte("Exif\x00\x00"), exif...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+	content := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, app1...), content[2:]...)
+}
+
+func TestResources_ImageMeta(t *testing.T) {
+	exif := testTIFF(
+		[]testIFDEntry{
+			{tag: 0x010F, typ: 2, count: 6, data: []byte("Canon\x00")},
+			{tag: 0x0112, typ: 3, count: 1, data: []byte{6, 0}},
+			{tag: 0x8769, typ: 4, count: 1, ifd: 1},
+			{tag: 0x8825, typ: 4, count: 1, ifd: 2},
+		},
+		[]testIFDEntry{
+			{tag: 0x9003, typ: 2, count: 20, data: []byte("2024:05:06 07:08:09\x00")},
+			{tag: 0x829D, typ: 5, count: 1, data: testRationals(28, 10)},
+			{tag: 0x8827, typ: 3, count: 1, data: []byte{100, 0}},
+		},
+		[]testIFDEntry{
+			{tag: 0x0001, typ: 2, count: 2, data: []byte("N\x00")},
+			{tag: 0x0002, typ: 5, count: 3, data: testRationals(52, 1, 30, 1, 0, 1)},
+			{tag: 0x0003, typ: 2, count: 2, data: []byte("W\x00")},
+			{tag: 0x0004, typ: 5, count: 3, data: testRationals(13, 1, 15, 1, 36, 1)},
+		},
+	)
+	rs := NewResources(fstest.MapFS{
+		"img/photo.jpg": &fstest.MapFile{Data: testJPEG(t, 30, 20, exif)},
+		"img/plain.jpg": &fstest.MapFile{Data: testJPEG(t, 30, 20, nil)},
+		"img/a.png":     &fstest.MapFile{Data: testPNG(t, 8, 4)},
+	}, "/assets")
+
+	photo, _ := rs.Get("img/photo.jpg")
+	cfg, err := rs.ImageConfig(photo)
+	if err != nil || cfg != (ImageConfig{Width: 30, Height: 20, Format: "jpeg"}) {
+		t.Errorf("config: %+v, error: %v", cfg, err)
+	}
+	x, err := rs.EXIF(photo)
+	if err != nil || x == nil {
+		t.Fatalf("exif: %v, error: %v", x, err)
+	}
+	if !x.Date.Equal(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)) {
+		t.Errorf("exif date: %v", x.Date)
+	}
+	if x.Tags["Make"] != "Canon" || x.Tags["Orientation"] != 6 || x.Tags["FNumber"] != 2.8 || x.Tags["ISOSpeedRatings"] != 100 {
+		t.Errorf("exif tags: %v", x.Tags)
+	}
+	if math.Abs(x.Lat-52.5) > 1e-9 || math.Abs(x.Long+13.26) > 1e-9 {
+		t.Errorf("exif lat: %v, long: %v", x.Lat, x.Long)
+	}
+
+	plain, _ := rs.Get("img/plain.jpg")
+	if x, err := rs.EXIF(plain); x != nil || err != nil {
+		t.Errorf("exif of plain jpeg: %v, error: %v", x, err)
+	}
+	png, _ := rs.Get("img/a.png")
+	if cfg, _ := rs.ImageConfig(png); cfg.Width != 8 || cfg.Height != 4 || cfg.Format != "png" {
+		t.Errorf("png config: %+v", cfg)
+	}
+
+	// Truncated exif data fails without panic
+	broken, _ := NewResources(fstest.MapFS{
+		"b.jpg": &fstest.MapFile{Data: testJPEG(t, 2, 2, exif[:30])},
+	}, "").Get("b.jpg")
+	if _, err := rs.EXIF(broken); err == nil {
+		t.Error("truncated exif is ok?")
+	}
+}
+
+func TestViewEngine_ImageMeta(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	gv.SetResources(NewResources(fstest.MapFS{
+		"img/photo.jpg": &fstest.MapFile{Data: testJPEG(t, 30, 20, nil)},
+	}, "/assets"))
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "imagemeta", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val, expect := buff.String(), `<v><img width="30" height="20">none</v>`; val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
@@ -0,0 +1,371 @@
+package goview
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// DefaultImageQuality default jpeg quality of processed images
+const DefaultImageQuality = 75
+
+// DefaultMaxInlineSize default maximum size in bytes of inlined images
+const DefaultMaxInlineSize = 32 << 10
+
+// DefaultMaxImageSize default maximum width and height in pixels of source and processed images
+const DefaultMaxImageSize = 8192
+
+// ImageOptions options of image processing
+type ImageOptions struct {
+	Quality   int    //jpeg quality from 1 to 100, 0 uses DefaultImageQuality
+	CacheDir  string //directory keeping processed images between restarts, empty keeps them in memory only
+	MaxInline int64  //maximum size in bytes of inlined images, 0 uses DefaultMaxInlineSize
+	MaxSize   int    //maximum width and height in pixels of source and processed images, 0 uses DefaultMaxImageSize
+}
+
+// SetImageOptions set the options of image processing
+func (rs *Resources) SetImageOptions(opts ImageOptions) {
+	rs.mu.Lock()
+	rs.images = opts
+	rs.mu.Unlock()
+}
+
+// imageSpec parsed image processing spec, such as "600x400 q80 png top"
+type imageSpec struct {
+	width, height int
+	quality       int
+	format        string
+	anchor        string
+	maxSize       int
+}
+
+// imageAnchors crop anchors as fractions of the free space on the x and y axis
+var imageAnchors = map[string][2]float64{
+	"topleft":     {0, 0},
+	"top":         {0.5, 0},
+	"topright":    {1, 0},
+	"left":        {0, 0.5},
+	"center":      {0.5, 0.5},
+	"right":       {1, 0.5},
+	"bottomleft":  {0, 1},
+	"bottom":      {0.5, 1},
+	"bottomright": {1, 1},
+}
+
+// parseImageSpec parse the space separated size "WxH", where a missing side keeps the aspect ratio,
+// quality "q80", format "jpg", "png" or "gif" and anchor such as "top"
+func parseImageSpec(spec string) (imageSpec, error) {
+	s := imageSpec{anchor: "center"}
+	for _, token := range strings.Fields(strings.ToLower(spec)) {
+		switch {
+		case strings.Contains(token, "x") && token[0] != 'q':
+			w, h, _ := strings.Cut(token, "x")
+			var err error
+			if w != "" {
+				if s.width, err = strconv.Atoi(w); err != nil || s.width < 0 {
+					return s, fmt.Errorf("invalid image width: %q", token)
+				}
+			}
+			if h != "" {
+				if s.height, err = strconv.Atoi(h); err != nil || s.height < 0 {
+					return s, fmt.Errorf("invalid image height: %q", token)
+				}
+			}
+		case token[0] == 'q':
+			q, err := strconv.Atoi(token[1:])
+			if err != nil || q < 1 || q > 100 {
+				return s, fmt.Errorf("invalid image quality: %q", token)
+			}
+			s.quality = q
+		case token == "jpg" || token == "jpeg" || token == "png" || token == "gif":
+			s.format = strings.Replace(token, "jpeg", "jpg", 1)
+		default:
+			if _, ok := imageAnchors[token]; !ok {
+				return s, fmt.Errorf("invalid image spec: %q", token)
+			}
+			s.anchor = token
+		}
+	}
+	if s.width == 0 && s.height == 0 {
+		return s, fmt.Errorf("invalid image spec, missing size: %q", spec)
+	}
+	return s, nil
+}
+
+// Resize scale the image to the size of the spec, such as "600x" or "600x400", which may change its aspect ratio
+func (rs *Resources) Resize(spec string, r *Resource) (*Resource, error) {
+	return rs.processImage("resize", spec, r, func(src image.Image, s imageSpec) (image.Image, error) {
+		b := src.Bounds()
+		w, h := s.width, s.height
+		if w == 0 {
+			w = max(b.Dx()*h/b.Dy(), 1)
+		}
+		if h == 0 {
+			h = max(b.Dy()*w/b.Dx(), 1)
+		}
+		if w > s.maxSize || h > s.maxSize {
+			return nil, fmt.Errorf("image size %vx%v exceeds %v pixels", w, h, s.maxSize)
+		}
+		return scaleImage(src, w, h), nil
+	})
+}
+
+// Fit scale the image down to fit into the box of the spec, keeping its aspect ratio
+func (rs *Resources) Fit(spec string, r *Resource) (*Resource, error) {
+	return rs.processImage("fit", spec, r, func(src image.Image, s imageSpec) (image.Image, error) {
+		b := src.Bounds()
+		scale := 1.0
+		if s.width > 0 {
+			scale = min(scale, float64(s.width)/float64(b.Dx()))
+		}
+		if s.height > 0 {
+			scale = min(scale, float64(s.height)/float64(b.Dy()))
+		}
+		if scale == 1 {
+			return src, nil
+		}
+		return scaleImage(src, max(int(float64(b.Dx())*scale+0.5), 1), max(int(float64(b.Dy())*scale+0.5), 1)), nil
+	})
+}
+
+// Fill scale the image to cover the size of the spec and crop it to the size at the anchor
+func (rs *Resources) Fill(spec string, r *Resource) (*Resource, error) {
+	return rs.processImage("fill", spec, r, func(src image.Image, s imageSpec) (image.Image, error) {
+		b := src.Bounds()
+		w, h := s.width, s.height
+		if w == 0 {
+			w = b.Dx()
+		}
+		if h == 0 {
+			h = b.Dy()
+		}
+		// Crop the source to the aspect ratio of the size first, so the scaled image is never
+		// larger than the size
+		scale := max(float64(w)/float64(b.Dx()), float64(h)/float64(b.Dy()))
+		cw := min(max(int(float64(w)/scale+0.5), 1), b.Dx())
+		ch := min(max(int(float64(h)/scale+0.5), 1), b.Dy())
+		return scaleImage(cropImage(src, cw, ch, s.anchor), w, h), nil
+	})
+}
+
+// Crop crop the image to the size of the spec at the anchor, without scaling
+func (rs *Resources) Crop(spec string, r *Resource) (*Resource, error) {
+	return rs.processImage("crop", spec, r, func(src image.Image, s imageSpec) (image.Image, error) {
+		w, h := s.width, s.height
+		if w == 0 {
+			w = src.Bounds().Dx()
+		}
+		if h == 0 {
+			h = src.Bounds().Dy()
+		}
+		return cropImage(src, w, h, s.anchor), nil
+	})
+}
+
+// processImage apply the operation to the image and encode the result, processed images are cached
+// in memory and in ImageOptions.CacheDir. The image is only decoded when it is not cached, images and
+// specs larger than ImageOptions.MaxSize fail.
+func (rs *Resources) processImage(op, spec string, r *Resource, fn func(image.Image, imageSpec) (image.Image, error)) (*Resource, error) {
+	s, err := parseImageSpec(spec)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: %v", op, r.name, err)
+	}
+	rs.mu.RLock()
+	opts := rs.images
+	rs.mu.RUnlock()
+	if s.quality == 0 {
+		s.quality = opts.Quality
+	}
+	if s.quality == 0 {
+		s.quality = DefaultImageQuality
+	}
+	if s.maxSize = opts.MaxSize; s.maxSize <= 0 {
+		s.maxSize = DefaultMaxImageSize
+	}
+	if s.width > s.maxSize || s.height > s.maxSize {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: spec size %vx%v exceeds %v pixels", op, r.name, s.width, s.height, s.maxSize)
+	}
+
+	// Only the header is read for the format and size, the image is decoded on a cache miss
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(r.content))
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: %v", op, r.name, err)
+	}
+	if cfg.Width > s.maxSize || cfg.Height > s.maxSize {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: image size %vx%v exceeds %v pixels", op, r.name, cfg.Width, cfg.Height, s.maxSize)
+	}
+	if s.format == "" {
+		s.format = strings.Replace(format, "jpeg", "jpg", 1)
+	}
+
+	key := sha256.Sum256([]byte(fmt.Sprintf("%x %v %d %d %d %v %v", r.sum, op, s.width, s.height, s.quality, s.format, s.anchor)))
+	ext := path.Ext(r.name)
+	name := strings.TrimSuffix(r.name, ext) + "_" + op + "_" + hex.EncodeToString(key[:8]) + "." + s.format
+	rs.mu.RLock()
+	out, ok := rs.compiled[name]
+	rs.mu.RUnlock()
+	if ok {
+		return out, nil
+	}
+
+	var cacheFile string
+	if opts.CacheDir != "" {
+		cacheFile = filepath.Join(opts.CacheDir, filepath.FromSlash(name))
+		if content, err := os.ReadFile(cacheFile); err == nil {
+			return rs.storeImage(name, newResource(name, content, rs.baseURL, r.modTime))
+		}
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(r.content))
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: %v", op, r.name, err)
+	}
+	img, err := fn(src, s)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: %v", op, r.name, err)
+	}
+	buf := new(bytes.Buffer)
+	if err := encodeImage(buf, img, s); err != nil {
+		return nil, fmt.Errorf("ViewEngine image %v name:%v, error: %v", op, r.name, err)
+	}
+	if cacheFile != "" {
+		if err := os.MkdirAll(filepath.Dir(cacheFile), 0o755); err != nil {
+			return nil, fmt.Errorf("ViewEngine image cache name:%v, error: %v", name, err)
+		}
+		if err := os.WriteFile(cacheFile, buf.Bytes(), 0o644); err != nil {
+			return nil, fmt.Errorf("ViewEngine image cache name:%v, error: %v", name, err)
+		}
+	}
+	return rs.storeImage(name, newResource(name, buf.Bytes(), rs.baseURL, r.modTime))
+}
+
+// Inline get the image as data URI, for embedding in HTML emails. Images larger than
+// ImageOptions.MaxInline fail.
+func (rs *Resources) Inline(r *Resource) (template.URL, error) {
+	rs.mu.RLock()
+	limit := rs.images.MaxInline
+	rs.mu.RUnlock()
+	if limit <= 0 {
+		limit = DefaultMaxInlineSize
+	}
+	mediaType, _, _ := strings.Cut(r.mediaType, ";")
+	if !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("ViewEngine image inline name:%v, error: not an image: %v", r.name, mediaType)
+	}
+	if size := int64(len(r.content)); size > limit {
+		return "", fmt.Errorf("ViewEngine image inline name:%v, error: size %v exceeds %v bytes", r.name, size, limit)
+	}
+	return template.URL("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(r.content)), nil
+}
+
+func (rs *Resources) storeImage(name string, r *Resource) (*Resource, error) {
+	r, err := rs.publish(r)
+	if err != nil {
+		return nil, err
+	}
+	rs.mu.Lock()
+	rs.compiled[name] = r
+	rs.mu.Unlock()
+	return r, nil
+}
+
+func scaleImage(src image.Image, w, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+	return dst
+}
+
+func cropImage(src image.Image, w, h int, anchor string) image.Image {
+	b := src.Bounds()
+	w, h = min(w, b.Dx()), min(h, b.Dy())
+	a := imageAnchors[anchor]
+	x := b.Min.X + int(float64(b.Dx()-w)*a[0])
+	y := b.Min.Y + int(float64(b.Dy()-h)*a[1])
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), src, image.Pt(x, y), draw.Src)
+	return dst
+}
+
+func encodeImage(buf *bytes.Buffer, img image.Image, s imageSpec) error {
+	switch s.format {
+	case "png":
+		return png.Encode(buf, img)
+	case "gif":
+		return gif.Encode(buf, img, nil)
+	case "jpg":
+		return jpeg.Encode(buf, img, &jpeg.Options{Quality: s.quality})
+	}
+	return fmt.Errorf("unsupported image format: %v", s.format)
+}
+
+// ImagesNamespace `images` template namespace, the image is a resource or the path of a resource
+type ImagesNamespace struct {
+	e *ViewEngine
+}
+
+// Resize scale the image to the size of the spec, see Resources.Resize
+func (n *ImagesNamespace) Resize(spec string, img any) (*Resource, error) {
+	return n.process(spec, img, (*Resources).Resize)
+}
+
+// Fit scale the image down to fit into the box of the spec, see Resources.Fit
+func (n *ImagesNamespace) Fit(spec string, img any) (*Resource, error) {
+	return n.process(spec, img, (*Resources).Fit)
+}
+
+// Fill scale and crop the image to the size of the spec, see Resources.Fill
+func (n *ImagesNamespace) Fill(spec string, img any) (*Resource, error) {
+	return n.process(spec, img, (*Resources).Fill)
+}
+
+// Crop crop the image to the size of the spec, see Resources.Crop
+func (n *ImagesNamespace) Crop(spec string, img any) (*Resource, error) {
+	return n.process(spec, img, (*Resources).Crop)
+}
+
+// Inline get the image as data URI, see Resources.Inline
+func (n *ImagesNamespace) Inline(img any) (template.URL, error) {
+	rs, r, err := n.resource(img)
+	if err != nil {
+		return "", err
+	}
+	return rs.Inline(r)
+}
+
+func (n *ImagesNamespace) process(spec string, img any, fn func(*Resources, string, *Resource) (*Resource, error)) (*Resource, error) {
+	rs, r, err := n.resource(img)
+	if err != nil {
+		return nil, err
+	}
+	return fn(rs, spec, r)
+}
+
+// resource get the resource of the image argument
+func (n *ImagesNamespace) resource(img any) (*Resources, *Resource, error) {
+	rs, err := (&ResourcesNamespace{e: n.e}).pipeline()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch v := img.(type) {
+	case *Resource:
+		return rs, v, nil
+	case string:
+		r, err := rs.Get(v)
+		return rs, r, err
+	}
+	return nil, nil, fmt.Errorf("ViewEngine image must be a resource or path, got %T", img)
+}
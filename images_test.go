@@ -0,0 +1,215 @@
+package goview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testPNG png of the size, the left half red and the right half blue
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestResources_Images(t *testing.T) {
+	rs := NewResources(fstest.MapFS{
+		"img/a.png": &fstest.MapFile{Data: testPNG(t, 200, 100)},
+	}, "/assets")
+	src, _ := rs.Get("img/a.png")
+
+	for _, v := range []struct {
+		Op     func(string, *Resource) (*Resource, error)
+		Spec   string
+		Width  int
+		Height int
+		Format string
+	}{
+		{Op: rs.Resize, Spec: "100x", Width: 100, Height: 50, Format: "png"},
+		{Op: rs.Resize, Spec: "x20 jpg q90", Width: 40, Height: 20, Format: "jpeg"},
+		{Op: rs.Resize, Spec: "50x50", Width: 50, Height: 50, Format: "png"},
+		{Op: rs.Fit, Spec: "80x80", Width: 80, Height: 40, Format: "png"},
+		{Op: rs.Fit, Spec: "400x400", Width: 200, Height: 100, Format: "png"},
+		{Op: rs.Fill, Spec: "50x50", Width: 50, Height: 50, Format: "png"},
+		{Op: rs.Crop, Spec: "20x10 gif", Width: 20, Height: 10, Format: "gif"},
+	} {
+		out, err := v.Op(v.Spec, src)
+		if err != nil {
+			t.Fatalf("spec: %v, error: %v", v.Spec, err)
+		}
+		cfg, format, err := image.DecodeConfig(strings.NewReader(out.Content()))
+		if err != nil {
+			t.Fatalf("spec: %v, decode error: %v", v.Spec, err)
+		}
+		if cfg.Width != v.Width || cfg.Height != v.Height || format != v.Format {
+			t.Errorf("spec: %v, actual: %vx%v %v, expect: %vx%v %v", v.Spec, cfg.Width, cfg.Height, format, v.Width, v.Height, v.Format)
+		}
+	}
+
+	// Crop anchors
+	for _, v := range []struct {
+		Spec string
+		Blue bool
+	}{
+		{Spec: "10x10 left", Blue: false},
+		{Spec: "10x10 right", Blue: true},
+	} {
+		out, _ := rs.Crop(v.Spec, src)
+		img, _ := png.Decode(strings.NewReader(out.Content()))
+		if _, _, b, _ := img.At(5, 5).RGBA(); (b > 0) != v.Blue {
+			t.Errorf("spec: %v, color: %v", v.Spec, img.At(5, 5))
+		}
+	}
+
+	a, _ := rs.Resize("100x", src)
+	b, _ := rs.Resize("100x", src)
+	if a != b || !strings.HasPrefix(a.Name(), "img/a_resize_") || !strings.HasSuffix(a.Name(), ".png") {
+		t.Errorf("processed image name: %v, cached: %v", a.Name(), a == b)
+	}
+	for _, spec := range []string{"", "axb", "100x q0", "100x webp", "100x middle"} {
+		if _, err := rs.Resize(spec, src); err == nil {
+			t.Errorf("spec %q is ok?", spec)
+		}
+	}
+}
+
+func TestResources_ImageCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	fsys := fstest.MapFS{"img/a.png": &fstest.MapFile{Data: testPNG(t, 20, 20)}}
+	rs := NewResources(fsys, "/assets")
+	rs.SetImageOptions(ImageOptions{CacheDir: dir})
+	src, _ := rs.Get("img/a.png")
+	out, err := rs.Fit("10x", src)
+	if err != nil {
+		t.Fatalf("fit error: %v", err)
+	}
+	cached, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(out.Name())))
+	if err != nil || !bytes.Equal(cached, []byte(out.Content())) {
+		t.Fatalf("cache file error: %v", err)
+	}
+
+	// A new pipeline reads the cache file instead of processing the image
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(out.Name())), []byte("cached"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs = NewResources(fsys, "/assets")
+	rs.SetImageOptions(ImageOptions{CacheDir: dir})
+	src, _ = rs.Get("img/a.png")
+	if out, _ := rs.Fit("10x", src); out.Content() != "cached" {
+		t.Errorf("cache file not used: %q", out.Content())
+	}
+}
+
+func TestResources_ImageMaxSize(t *testing.T) {
+	rs := NewResources(fstest.MapFS{
+		"img/a.png":    &fstest.MapFile{Data: testPNG(t, 200, 100)},
+		"img/tall.png": &fstest.MapFile{Data: testPNG(t, 2, 100)},
+	}, "/assets")
+	rs.SetImageOptions(ImageOptions{MaxSize: 150})
+	src, _ := rs.Get("img/a.png")
+	tall, _ := rs.Get("img/tall.png")
+
+	for _, v := range []struct {
+		Op   func(string, *Resource) (*Resource, error)
+		Spec string
+		Src  *Resource
+		Err  string
+	}{
+		{Op: rs.Fit, Spec: "100x", Src: src, Err: "image size 200x100 exceeds 150 pixels"},
+		{Op: rs.Resize, Spec: "200x", Src: tall, Err: "spec size 200x0 exceeds 150 pixels"},
+		{Op: rs.Resize, Spec: "100x", Src: tall, Err: "image size 100x5000 exceeds 150 pixels"},
+	} {
+		if _, err := v.Op(v.Spec, v.Src); err == nil || !strings.Contains(err.Error(), v.Err) {
+			t.Errorf("spec: %v, error: %v, expect: %v", v.Spec, err, v.Err)
+		}
+	}
+
+	// Fill crops before scaling, the scaled image is never larger than the spec
+	out, err := rs.Fill("100x100", tall)
+	if err != nil {
+		t.Fatalf("fill error: %v", err)
+	}
+	if cfg, _, _ := image.DecodeConfig(strings.NewReader(out.Content())); cfg.Width != 100 || cfg.Height != 100 {
+		t.Errorf("fill size: %vx%v", cfg.Width, cfg.Height)
+	}
+}
+
+func TestViewEngine_Images(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	gv.SetResources(NewResources(fstest.MapFS{
+		"img/a.png": &fstest.MapFile{Data: testPNG(t, 200, 100)},
+	}, "/assets"))
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "images", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val := buff.String(); !strings.HasPrefix(val, `<v><img src="/assets/img/a_fill_`) || !strings.HasSuffix(val, `.jpg"></v>`) {
+		t.Errorf("actual: %v", val)
+	}
+}
+
+func TestResources_Inline(t *testing.T) {
+	data := testPNG(t, 4, 4)
+	rs := NewResources(fstest.MapFS{
+		"img/a.png":   &fstest.MapFile{Data: data},
+		"css/app.css": &fstest.MapFile{Data: []byte("a{}")},
+	}, "/assets")
+	img, _ := rs.Get("img/a.png")
+	uri, err := rs.Inline(img)
+	if err != nil {
+		t.Fatalf("inline error: %v", err)
+	}
+	if expect := "data:image/png;base64," + base64.StdEncoding.EncodeToString(data); string(uri) != expect {
+		t.Errorf("actual: %v, expect: %v", uri, expect)
+	}
+	css, _ := rs.Get("css/app.css")
+	if _, err := rs.Inline(css); err == nil {
+		t.Error("inline css is ok?")
+	}
+	rs.SetImageOptions(ImageOptions{MaxInline: 10})
+	if _, err := rs.Inline(img); err == nil || !strings.Contains(err.Error(), "exceeds 10 bytes") {
+		t.Errorf("inline large image error: %v", err)
+	}
+
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	gv.SetResources(NewResources(fstest.MapFS{"img/a.png": &fstest.MapFile{Data: data}}, "/assets"))
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "inline", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val := buff.String(); val != `<v><img src="`+string(uri)+`"></v>` {
+		t.Errorf("actual: %v", val)
+	}
+	if err := gv.RenderWriter(buff, "inline", M{"path": "../../etc/passwd"}); err == nil {
+		t.Error("inline outside the resources is ok?")
+	}
+}
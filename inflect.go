@@ -0,0 +1,166 @@
+package goview
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// InflectNamespace `inflect` template namespace, English pluralization and singularization
+type InflectNamespace struct{}
+
+var inflectNamespace = &InflectNamespace{}
+
+type inflectRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func newInflectRules(rules ...string) []inflectRule {
+	out := make([]inflectRule, 0, len(rules)/2)
+	for i := 0; i+1 < len(rules); i += 2 {
+		out = append(out, inflectRule{regexp.MustCompile(rules[i]), rules[i+1]})
+	}
+	return out
+}
+
+// pluralRules suffix rules of plurals, the first matching rule applies
+var pluralRules = newInflectRules(
+	`(quiz)$`, "${1}zes",
+	`(matr|vert|ind)(ix|ex)$`, "${1}ices",
+	`(alias|status|bus|virus|campus)$`, "${1}es",
+	`(analy|ba|diagno|parenthe|progno|synop|the|cri)sis$`, "${1}ses",
+	`(x|ch|ss|sh|z|s)$`, "${1}es",
+	`([^aeiouy]|qu)y$`, "${1}ies",
+	`$`, "s",
+)
+
+// singularRules suffix rules of singulars, the first matching rule applies
+var singularRules = newInflectRules(
+	`(quiz)zes$`, "${1}",
+	`(matr)ices$`, "${1}ix",
+	`(vert|ind)ices$`, "${1}ex",
+	`(alias|status|bus|virus|campus)es$`, "${1}",
+	`(analy|ba|diagno|parenthe|progno|synop|the|cri)ses$`, "${1}sis",
+	`(x|ch|ss|sh|z)es$`, "${1}",
+	`([^aeiouy]|qu)ies$`, "${1}y",
+	`(ss|us|is)$`, "${1}",
+	`s$`, "",
+)
+
+// irregularWords singular and plural of words not following the rules
+var irregularWords = map[string]string{
+	"person":     "people",
+	"man":        "men",
+	"woman":      "women",
+	"child":      "children",
+	"tooth":      "teeth",
+	"foot":       "feet",
+	"mouse":      "mice",
+	"goose":      "geese",
+	"ox":         "oxen",
+	"leaf":       "leaves",
+	"knife":      "knives",
+	"wife":       "wives",
+	"life":       "lives",
+	"wolf":       "wolves",
+	"half":       "halves",
+	"shelf":      "shelves",
+	"calf":       "calves",
+	"thief":      "thieves",
+	"hero":       "heroes",
+	"potato":     "potatoes",
+	"tomato":     "tomatoes",
+	"echo":       "echoes",
+	"movie":      "movies",
+	"cookie":     "cookies",
+	"zombie":     "zombies",
+	"calorie":    "calories",
+	"pie":        "pies",
+	"tie":        "ties",
+	"cactus":     "cacti",
+	"criterion":  "criteria",
+	"phenomenon": "phenomena",
+}
+
+// irregularPlurals plurals of irregularWords
+var irregularPlurals = func() map[string]string {
+	out := make(map[string]string, len(irregularWords))
+	for singular, plural := range irregularWords {
+		out[plural] = singular
+	}
+	return out
+}()
+
+// uncountableWords words with the same singular and plural
+var uncountableWords = map[string]bool{
+	"equipment":   true,
+	"information": true,
+	"rice":        true,
+	"money":       true,
+	"species":     true,
+	"series":      true,
+	"fish":        true,
+	"sheep":       true,
+	"deer":        true,
+	"news":        true,
+	"police":      true,
+	"feedback":    true,
+	"software":    true,
+}
+
+// Pluralize get the plural of the English word, such as "people" for "person"
+func (*InflectNamespace) Pluralize(word string) string {
+	if _, ok := irregularPlurals[strings.ToLower(word)]; ok {
+		return word
+	}
+	return inflect(word, irregularWords, pluralRules)
+}
+
+// Singularize get the singular of the English word, such as "person" for "people"
+func (*InflectNamespace) Singularize(word string) string {
+	if _, ok := irregularWords[strings.ToLower(word)]; ok {
+		return word
+	}
+	return inflect(word, irregularPlurals, singularRules)
+}
+
+// PluralizeWithCount prefix the word with the count, plural unless the count is 1, such as "3 people"
+func (n *InflectNamespace) PluralizeWithCount(count any, word string) (string, error) {
+	f, err := toFloat(count)
+	if err != nil {
+		return "", err
+	}
+	if f != 1 {
+		word = n.Pluralize(word)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64) + " " + word, nil
+}
+
+// inflect apply the irregular words or the first matching rule, keeping the case of the word
+func inflect(word string, irregular map[string]string, rules []inflectRule) string {
+	lower := strings.ToLower(word)
+	if lower == "" || uncountableWords[lower] {
+		return word
+	}
+	out, ok := irregular[lower]
+	if !ok {
+		out = lower
+		for _, rule := range rules {
+			if rule.pattern.MatchString(lower) {
+				out = rule.pattern.ReplaceAllString(lower, rule.replacement)
+				break
+			}
+		}
+	}
+	if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+		if len(word) > 1 && word == strings.ToUpper(word) {
+			return strings.ToUpper(out)
+		}
+		_, size := utf8.DecodeRuneInString(out)
+		return strings.ToUpper(out[:size]) + out[size:]
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package goview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInflectNamespace(t *testing.T) {
+	n := inflectNamespace
+	for _, v := range []struct {
+		Singular string
+		Plural   string
+	}{
+		{Singular: "post", Plural: "posts"},
+		{Singular: "category", Plural: "categories"},
+		{Singular: "day", Plural: "days"},
+		{Singular: "box", Plural: "boxes"},
+		{Singular: "church", Plural: "churches"},
+		{Singular: "class", Plural: "classes"},
+		{Singular: "status", Plural: "statuses"},
+		{Singular: "analysis", Plural: "analyses"},
+		{Singular: "index", Plural: "indices"},
+		{Singular: "quiz", Plural: "quizzes"},
+		{Singular: "person", Plural: "people"},
+		{Singular: "child", Plural: "children"},
+		{Singular: "knife", Plural: "knives"},
+		{Singular: "sheep", Plural: "sheep"},
+		{Singular: "movie", Plural: "movies"},
+		{Singular: "cookie", Plural: "cookies"},
+		{Singular: "zombie", Plural: "zombies"},
+		{Singular: "Cookie", Plural: "Cookies"},
+		{Singular: "Person", Plural: "People"},
+		{Singular: "URL", Plural: "URLS"},
+	} {
+		if val := n.Pluralize(v.Singular); val != v.Plural {
+			t.Errorf("pluralize %v actual: %v, expect: %v", v.Singular, val, v.Plural)
+		}
+		if val := n.Singularize(v.Plural); val != v.Singular {
+			t.Errorf("singularize %v actual: %v, expect: %v", v.Plural, val, v.Singular)
+		}
+	}
+	for _, v := range []struct {
+		Count  any
+		Expect string
+	}{
+		{Count: 1, Expect: "1 person"},
+		{Count: 3, Expect: "3 people"},
+		{Count: 0, Expect: "0 people"},
+		{Count: 1.5, Expect: "1.5 people"},
+	} {
+		val, err := n.PluralizeWithCount(v.Count, "person")
+		if err != nil {
+			t.Fatalf("count: %v, error: %v", v.Count, err)
+		}
+		if val != v.Expect {
+			t.Errorf("count: %v, actual: %v, expect: %v", v.Count, val, v.Expect)
+		}
+	}
+}
+
+func TestViewEngine_Inflect(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "inflect", M{"n": 3}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := "<v>categories Person 3 people</v>"
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
@@ -19,3 +19,19 @@ func Render(w http.ResponseWriter, status int, name string, data any) error {
 	}
 	return instance.Render(w, status, name, data)
 }
+
+// Negotiate render view template, json or xml by the request Accept header with default instance
+func Negotiate(w http.ResponseWriter, r *http.Request, status int, name string, data any) error {
+	if instance == nil {
+		instance = Default()
+	}
+	return instance.Negotiate(w, r, status, name, data)
+}
+
+// RenderRequest render view template for the request with default instance, see ViewEngine.RenderRequest
+func RenderRequest(w http.ResponseWriter, r *http.Request, status int, name string, data any) error {
+	if instance == nil {
+		instance = Default()
+	}
+	return instance.RenderRequest(w, r, status, name, data)
+}
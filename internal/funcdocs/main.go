@@ -0,0 +1,128 @@
+// Command funcdocs generates the doc strings of the template function reference from the doc comments
+// of the namespace methods and of the function registrations in newRenderContext.
+//
+// It is run by go generate in the goview package directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const output = "funcdocs_gen.go"
+
+func main() {
+	docs, err := collect(".")
+	if err != nil {
+		log.Fatalf("funcdocs: %v", err)
+	}
+	src, err := generate(docs)
+	if err != nil {
+		log.Fatalf("funcdocs: %v", err)
+	}
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		log.Fatalf("funcdocs: %v", err)
+	}
+}
+
+// collect get the docs of the package in dir, keyed by function name or namespace type and method
+func collect(dir string) (map[string]string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			cmap := ast.NewCommentMap(fset, file, file.Comments)
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				if fd.Recv != nil {
+					if typ := receiverType(fd); strings.HasSuffix(typ, "Namespace") && fd.Name.IsExported() && fd.Doc != nil {
+						docs[typ+"."+fd.Name.Name] = docText(fd.Doc)
+					}
+					if fd.Name.Name == "newRenderContext" {
+						registrations(fd, cmap, docs)
+					}
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+// receiverType get the type name of the method receiver
+func receiverType(fd *ast.FuncDecl) string {
+	expr := fd.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// registrations get the comments of the `renderCtx.Funcs["name"] = ...` statements
+func registrations(fd *ast.FuncDecl, cmap ast.CommentMap, docs map[string]string) {
+	for _, stmt := range fd.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 {
+			continue
+		}
+		index, ok := assign.Lhs[0].(*ast.IndexExpr)
+		if !ok {
+			continue
+		}
+		key, ok := index.Index.(*ast.BasicLit)
+		if !ok || key.Kind != token.STRING {
+			continue
+		}
+		name, err := strconv.Unquote(key.Value)
+		if err != nil {
+			continue
+		}
+		if groups := cmap[stmt]; len(groups) > 0 {
+			docs[name] = docText(groups[0])
+		}
+	}
+}
+
+// docText get the comment text as a single line
+func docText(group *ast.CommentGroup) string {
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+func generate(docs map[string]string) ([]byte, error) {
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by go run ./internal/funcdocs; DO NOT EDIT.\n\npackage goview\n\n")
+	buf.WriteString("// funcDocs docs of the template functions and namespace methods, keyed by function name\n")
+	buf.WriteString("// or namespace type and method, such as \"HumanizeNamespace.Comma\"\n")
+	buf.WriteString("var funcDocs = map[string]string{\n")
+	for _, k := range keys {
+		fmt.Fprintf(buf, "\t%q: %q,\n", k, docs[k])
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
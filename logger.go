@@ -0,0 +1,18 @@
+package goview
+
+import (
+	"log/slog"
+)
+
+// SetLogger set the structured logger for cache, reload, parse error and slow render events, nil disables logging
+func (e *ViewEngine) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	e.logger = logger
+}
+
+// Logger get the structured logger of the engine
+func (e *ViewEngine) Logger() *slog.Logger {
+	return e.logger
+}
@@ -0,0 +1,67 @@
+package goview
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics render metrics observer interface
+type Metrics interface {
+	// ObserveRender called after every top-level render with its duration and error
+	ObserveRender(name string, duration time.Duration, err error)
+	// ObserveCache called on every template lookup, hit is false when the template had to be compiled
+	ObserveCache(name string, hit bool)
+}
+
+// SetMetrics set render metrics observer, nil disables metrics
+func (e *ViewEngine) SetMetrics(metrics Metrics) {
+	e.metrics = metrics
+}
+
+// ExpvarMetrics render metrics published with expvar
+type ExpvarMetrics struct {
+	renders     *expvar.Map
+	errors      *expvar.Map
+	durations   *expvar.Map
+	cacheHits   *expvar.Map
+	cacheMisses *expvar.Map
+}
+
+var _ Metrics = (*ExpvarMetrics)(nil)
+
+// NewExpvarMetrics new expvar metrics, published as a map under the given name.
+// It panics if the name is already registered, like expvar.Publish.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		renders:     new(expvar.Map).Init(),
+		errors:      new(expvar.Map).Init(),
+		durations:   new(expvar.Map).Init(),
+		cacheHits:   new(expvar.Map).Init(),
+		cacheMisses: new(expvar.Map).Init(),
+	}
+	root := expvar.NewMap(name)
+	root.Set("renders", m.renders)
+	root.Set("errors", m.errors)
+	root.Set("duration_seconds", m.durations)
+	root.Set("cache_hits", m.cacheHits)
+	root.Set("cache_misses", m.cacheMisses)
+	return m
+}
+
+// ObserveRender count the render and add its duration in seconds
+func (m *ExpvarMetrics) ObserveRender(name string, duration time.Duration, err error) {
+	m.renders.Add(name, 1)
+	m.durations.AddFloat(name, duration.Seconds())
+	if err != nil {
+		m.errors.Add(name, 1)
+	}
+}
+
+// ObserveCache count the cache hit or miss
+func (m *ExpvarMetrics) ObserveCache(name string, hit bool) {
+	if hit {
+		m.cacheHits.Add(name, 1)
+	} else {
+		m.cacheMisses.Add(name, 1)
+	}
+}
@@ -0,0 +1,221 @@
+package goview
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Minifier minifier of rendered output, the method set of github.com/tdewolff/minify *minify.M
+type Minifier interface {
+	// Minify minify the content of the media type, such as "text/html", "text/css" or "text/javascript"
+	Minify(mediaType string, w io.Writer, r io.Reader) error
+}
+
+// SetMinifier set the minifier of Config.Minify output and the minify template functions,
+// nil restores the builtin minifier
+func (e *ViewEngine) SetMinifier(m Minifier) {
+	e.minifier = m
+}
+
+// getMinifier get the engine minifier, the builtin minifier when none is set
+func (e *ViewEngine) getMinifier() Minifier {
+	if e.minifier == nil {
+		return BuiltinMinifier{}
+	}
+	return e.minifier
+}
+
+// minifyString minify the string with the engine minifier
+func (e *ViewEngine) minifyString(mediaType, s string) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := e.getMinifier().Minify(mediaType, buf, strings.NewReader(s)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// BuiltinMinifier conservative minifier without dependencies. HTML whitespace is collapsed and
+// comments are removed, except in pre, textarea and script, inline styles are minified as CSS.
+// CSS comments and whitespace are removed. Other media types, such as JavaScript, are copied unchanged.
+type BuiltinMinifier struct{}
+
+var _ Minifier = BuiltinMinifier{}
+
+// Minify minify html and css, copy other media types
+func (BuiltinMinifier) Minify(mediaType string, w io.Writer, r io.Reader) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	switch mediaType, _, _ = strings.Cut(mediaType, ";"); strings.TrimSpace(mediaType) {
+	case "text/html":
+		_, err = w.Write(minifyHTML(src))
+	case "text/css":
+		_, err = w.Write(minifyCSS(src))
+	default:
+		_, err = w.Write(src)
+	}
+	return err
+}
+
+// rawTags elements whose content is not html
+var rawTags = map[string]bool{"pre": true, "textarea": true, "script": true, "style": true}
+
+func minifyHTML(src []byte) []byte {
+	out := make([]byte, 0, len(src))
+	space := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		if isSpace(c) {
+			space = true
+			i++
+			continue
+		}
+		if c != '<' {
+			if space && len(out) > 0 {
+				out = append(out, ' ')
+			}
+			space = false
+			out = append(out, c)
+			i++
+			continue
+		}
+
+		if bytes.HasPrefix(src[i:], []byte("<!--")) {
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + 7
+			}
+			// Keep conditional comments
+			if bytes.HasPrefix(src[i:], []byte("<!--[if")) {
+				if space && len(out) > 0 {
+					out = append(out, ' ')
+				}
+				space = false
+				out = append(out, src[i:end]...)
+			}
+			i = end
+			continue
+		}
+
+		end := tagEnd(src, i)
+		if space && len(out) > 0 {
+			out = append(out, ' ')
+		}
+		space = false
+		out = append(out, src[i:end]...)
+		name := tagName(src[i:end])
+		i = end
+		if !rawTags[name] || bytes.HasSuffix(src[:end], []byte("/>")) {
+			continue
+		}
+		closing := indexFold(src, "</"+name, i)
+		if closing < 0 {
+			closing = len(src)
+		}
+		if name == "style" {
+			out = append(out, minifyCSS(src[i:closing])...)
+		} else {
+			out = append(out, src[i:closing]...)
+		}
+		i = closing
+	}
+	return out
+}
+
+// tagEnd get the index after the '>' closing the tag starting at i, skipping quoted attribute values
+func tagEnd(src []byte, i int) int {
+	var quote byte
+	for j := i + 1; j < len(src); j++ {
+		switch c := src[j]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// tagName get the lower case name of an opening tag, empty for closing tags and declarations
+func tagName(tag []byte) string {
+	j := 1
+	for j < len(tag) && (tag[j] >= 'a' && tag[j] <= 'z' || tag[j] >= 'A' && tag[j] <= 'Z' || tag[j] >= '0' && tag[j] <= '9') {
+		j++
+	}
+	return strings.ToLower(string(tag[1:j]))
+}
+
+// indexFold get the index of the ASCII sub string from the index, ignoring case
+func indexFold(src []byte, sub string, from int) int {
+	for i := from; i+len(sub) <= len(src); i++ {
+		if strings.EqualFold(string(src[i:i+len(sub)]), sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func minifyCSS(src []byte) []byte {
+	out := make([]byte, 0, len(src))
+	space := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case isSpace(c):
+			space = true
+			i++
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + 4
+			}
+			// Keep license comments
+			if i+2 < len(src) && src[i+2] == '!' {
+				out = append(out, src[i:end]...)
+			} else {
+				space = true
+			}
+			i = end
+			continue
+		}
+
+		if space && len(out) > 0 && !strings.ContainsRune("{};,>:", rune(out[len(out)-1])) && !strings.ContainsRune("{};,>", rune(c)) {
+			out = append(out, ' ')
+		}
+		space = false
+		if c == '"' || c == '\'' {
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(src))
+			out = append(out, src[i:end]...)
+			i = end
+			continue
+		}
+		if c == '}' && len(out) > 0 && out[len(out)-1] == ';' {
+			out = out[:len(out)-1]
+		}
+		out = append(out, c)
+		i++
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package goview
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinMinifier(t *testing.T) {
+	for _, v := range []struct {
+		MediaType string
+		In        string
+		Expect    string
+	}{
+		{MediaType: "text/html", In: "\n <p>\n  a   b\n </p> <p>c</p>\n", Expect: "<p> a b </p> <p>c</p>"},
+		{MediaType: "text/html", In: `<a title="x   y">z</a>`, Expect: `<a title="x   y">z</a>`},
+		{MediaType: "text/html", In: "a <!-- x --> b <!--[if IE]>ie<![endif]-->", Expect: "a b <!--[if IE]>ie<![endif]-->"},
+		{MediaType: "text/html", In: "<PRE> a\n  b </PRE>  <textarea>\n x</textarea>", Expect: "<PRE> a\n  b </PRE> <textarea>\n x</textarea>"},
+		{MediaType: "text/html", In: "<script>var a  = 1;</script>", Expect: "<script>var a  = 1;</script>"},
+		{MediaType: "text/html", In: "<style>\n a { color : red ; }\n</style>", Expect: "<style>a{color :red}</style>"},
+		{MediaType: "text/html; charset=utf-8", In: "<br/>  <script/> x", Expect: "<br/> <script/> x"},
+		{MediaType: "text/css", In: "/* c */ a > b ,\n i:hover {\n  margin: 0 auto;\n  content: \"a  ;}\";\n}", Expect: "a>b,i:hover{margin:0 auto;content:\"a  ;}\"}"},
+		{MediaType: "text/css", In: "/*! license */ div :first-child { width: calc(1px + 2px) }", Expect: "/*! license */ div :first-child{width:calc(1px + 2px)}"},
+		{MediaType: "text/javascript", In: "var a  = 1;", Expect: "var a  = 1;"},
+	} {
+		buf := new(bytes.Buffer)
+		if err := (BuiltinMinifier{}).Minify(v.MediaType, buf, strings.NewReader(v.In)); err != nil {
+			t.Fatalf("minify error: %v", err)
+		}
+		if val := buf.String(); val != v.Expect {
+			t.Errorf("in: %q, actual: %q, expect: %q", v.In, val, v.Expect)
+		}
+	}
+}
+
+type upperMinifier struct{}
+
+func (upperMinifier) Minify(mediaType string, w io.Writer, r io.Reader) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(mediaType + ":" + strings.ToUpper(string(src))))
+	return err
+}
+
+func TestViewEngine_Minify(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		Minify:    true,
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "minify", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := `<v> <ul class="a  b"> <li> one </li> <li><b>two</b> <i>three</i></li> </ul> <pre>
+  keep   this
+  </pre> <style>.x{color:red}</style> </v>`
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %q, expect: %q", val, expect)
+	}
+
+	gv.SetMinifier(upperMinifier{})
+	buff.Reset()
+	if err := gv.RenderWriter(buff, "minify", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val := buff.String(); !strings.HasPrefix(val, "text/html:") || !strings.Contains(val, "TEXT/CSS:.X { COLOR: RED; }") {
+		t.Errorf("custom minifier output: %q", val)
+	}
+}
@@ -0,0 +1,172 @@
+package goview
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// JSONContentType content type for negotiated json output
+var JSONContentType = []string{"application/json; charset=utf-8"}
+
+// XMLContentType content type for negotiated xml output
+var XMLContentType = []string{"application/xml; charset=utf-8"}
+
+// Negotiate render the template, or the data as json or xml, depending on the request Accept header.
+// Function values are left out of json and xml output. Template output is the fallback
+// when the Accept header is missing or matches none of the formats. The response varies by Accept.
+func (e *ViewEngine) Negotiate(w http.ResponseWriter, r *http.Request, statusCode int, name string, data any, opts ...RenderOption) error {
+	w.Header().Add("Vary", "Accept")
+	tplType, _, _ := mime.ParseMediaType(e.ContentType()[0])
+	switch NegotiateFormat(r.Header.Get("Accept"), tplType, "application/json", "application/xml", "text/xml") {
+	case "application/json":
+		return writeData(w, statusCode, JSONContentType, func() ([]byte, error) {
+			return json.Marshal(exportData(data))
+		})
+	case "application/xml", "text/xml":
+		return writeData(w, statusCode, XMLContentType, func() ([]byte, error) {
+			out, err := xml.Marshal(exportData(data))
+			return append([]byte(xml.Header), out...), err
+		})
+	}
+	return e.RenderRequest(w, r, statusCode, name, data, opts...)
+}
+
+// NegotiateFormat return the offered media type that best matches the Accept header,
+// or the first offer if the header is empty or nothing matches.
+func NegotiateFormat(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	best, bestQ, bestSpecificity := offers[0], 0.0, -1
+	for _, spec := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(spec))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		for _, offer := range offers {
+			specificity := matchMediaType(mediaType, offer)
+			if specificity < 0 {
+				continue
+			}
+			if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+				best, bestQ, bestSpecificity = offer, q, specificity
+			}
+			// Offers are in preference order, only the first match of a range counts
+			break
+		}
+	}
+	return best
+}
+
+// matchMediaType return how specific the media range matches the offer, -1 for no match
+func matchMediaType(mediaRange, offer string) int {
+	if mediaRange == offer {
+		return 2
+	}
+	rangeType, rangeSub, _ := strings.Cut(mediaRange, "/")
+	offerType, _, _ := strings.Cut(offer, "/")
+	if rangeSub == "*" && (rangeType == "*" || rangeType == offerType) {
+		if rangeType == "*" {
+			return 0
+		}
+		return 1
+	}
+	return -1
+}
+
+func writeData(w http.ResponseWriter, statusCode int, contentType []string, marshal func() ([]byte, error)) error {
+	out, err := marshal()
+	if err != nil {
+		return err
+	}
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = contentType
+	}
+	w.WriteHeader(statusCode)
+	_, err = w.Write(out)
+	return err
+}
+
+// exportData drop function values from map data, which are template helpers and can't be encoded
+func exportData(data any) any {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return data
+	}
+	out := make(M, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		val := iter.Value()
+		if val.Kind() == reflect.Interface {
+			if val.IsNil() {
+				out[iter.Key().String()] = nil
+				continue
+			}
+			val = val.Elem()
+		}
+		if val.Kind() == reflect.Func {
+			continue
+		}
+		out[iter.Key().String()] = exportData(val.Interface())
+	}
+	return out
+}
+
+// MarshalXML encode the map as <data> with one child element per key, in key order.
+// Keys that are not valid XML names fail.
+func (m M) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "M" {
+		start.Name.Local = "data"
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if !isXMLName(k) {
+			return fmt.Errorf("invalid xml element name: %q", k)
+		}
+		if err := enc.EncodeElement(m[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// isXMLName check if the name is a valid XML element name without namespace prefix
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package goview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	offers := []string{"text/html", "application/json", "application/xml"}
+	for accept, expect := range map[string]string{
+		"":                                   "text/html",
+		"*/*":                                "text/html",
+		"application/json":                   "application/json",
+		"application/*":                      "application/json",
+		"text/html;q=0.8, application/xml":   "application/xml",
+		"application/json;q=0, */*;q=0.1":    "text/html",
+		"image/png":                          "text/html",
+		"text/*;q=0.5, application/json;q=1": "application/json",
+	} {
+		if val := NegotiateFormat(accept, offers...); val != expect {
+			t.Errorf("accept: %v, actual: %v, expect: %v", accept, val, expect)
+		}
+	}
+}
+
+func TestViewEngine_Negotiate(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	data := M{
+		"title": "Index",
+		"sum": func(a int, b int) int {
+			return a + b
+		},
+	}
+	for accept, expect := range map[string]string{
+		"text/html":        "<v>Index</v>",
+		"application/json": `{"title":"Index"}`,
+		"application/xml":  `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<data><title>Index</title></data>`,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		recorder := httptest.NewRecorder()
+		if err := gv.Negotiate(recorder, req, http.StatusOK, "index", data); err != nil {
+			t.Errorf("negotiate error: %v", err)
+			continue
+		}
+		assertRecorder(t, recorder, http.StatusOK, expect)
+		if vary := recorder.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("accept: %v, vary: %q", accept, vary)
+		}
+	}
+
+	for _, key := range []string{"a b", "1st", "<x>", "xmlns", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "application/xml")
+		if err := gv.Negotiate(httptest.NewRecorder(), req, http.StatusOK, "index", M{key: 1}); err == nil {
+			t.Errorf("xml key %q is ok?", key)
+		}
+	}
+}
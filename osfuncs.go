@@ -0,0 +1,80 @@
+package goview
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// MaxContentFileSize maximum size in bytes of files read by `os.ReadFile`
+const MaxContentFileSize = 1 << 20
+
+// SetContentFS set the content root of the `os` template namespace. Templates can't read files outside
+// of it, use the FS of os.OpenRoot("content") rather than os.DirFS, which follows symlinks out of the root.
+func (e *ViewEngine) SetContentFS(fsys fs.FS) {
+	e.contentFS = fsys
+}
+
+// OSNamespace `os` template namespace, file access restricted to the content root, see SetContentFS
+type OSNamespace struct {
+	e *ViewEngine
+}
+
+// root get the content root and the cleaned name, relative to the root
+func (n *OSNamespace) root(name string) (fs.FS, string, error) {
+	if n.e.contentFS == nil {
+		return nil, "", fmt.Errorf("ViewEngine content root not set, see SetContentFS")
+	}
+	clean := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	if clean == "/" {
+		return n.e.contentFS, ".", nil
+	}
+	if clean = clean[1:]; !fs.ValidPath(clean) {
+		return nil, "", fmt.Errorf("ViewEngine content invalid path: %q", name)
+	}
+	return n.e.contentFS, clean, nil
+}
+
+// ReadFile get the content of the file, such as "CHANGELOG.md", files larger than MaxContentFileSize fail
+func (n *OSNamespace) ReadFile(name string) (string, error) {
+	fsys, clean, err := n.root(name)
+	if err != nil {
+		return "", err
+	}
+	f, err := fsys.Open(clean)
+	if err != nil {
+		return "", fmt.Errorf("ViewEngine content read file name:%v, error: %w", name, err)
+	}
+	defer f.Close()
+	b, err := io.ReadAll(io.LimitReader(f, MaxContentFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("ViewEngine content read file name:%v, error: %w", name, err)
+	}
+	if len(b) > MaxContentFileSize {
+		return "", fmt.Errorf("ViewEngine content read file name:%v, error: size exceeds %v bytes", name, MaxContentFileSize)
+	}
+	return string(b), nil
+}
+
+// ReadDir get the entries of the directory sorted by name, "/" or "." for the content root
+func (n *OSNamespace) ReadDir(name string) ([]fs.FileInfo, error) {
+	fsys, clean, err := n.root(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(fsys, clean)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine content read dir name:%v, error: %w", name, err)
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("ViewEngine content read dir name:%v, error: %w", name, err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
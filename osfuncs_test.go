@@ -0,0 +1,59 @@
+package goview
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestViewEngine_OS(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	if err := gv.RenderWriter(new(bytes.Buffer), "os", nil); err == nil {
+		t.Errorf("expect content root error")
+	}
+
+	gv.SetContentFS(fstest.MapFS{
+		"CHANGELOG.md":      {Data: []byte("v1.0.0")},
+		"docs/intro.md":     {Data: []byte("intro")},
+		"docs/guide/a.md":   {Data: []byte("a")},
+		"docs/reference.md": {Data: []byte("reference")},
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "os", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val, expect := buff.String(), "<v>v1.0.0|guide:0/ intro.md:5 reference.md:9 </v>"; val != expect {
+		t.Errorf("actual: %q, expect: %q", val, expect)
+	}
+}
+
+func TestOSNamespace(t *testing.T) {
+	gv := New(DefaultConfig)
+	gv.SetContentFS(fstest.MapFS{
+		"docs/a.md":   {Data: []byte("a")},
+		"docs/big.md": {Data: make([]byte, MaxContentFileSize+1)},
+	})
+	n := &OSNamespace{e: gv}
+	for _, name := range []string{"docs/a.md", "/docs/a.md", "../docs/a.md", "docs/../docs/a.md", `docs\a.md`} {
+		if val, err := n.ReadFile(name); err != nil || val != "a" {
+			t.Errorf("name: %v, actual: %q, error: %v", name, val, err)
+		}
+	}
+	if _, err := n.ReadFile("docs/missing.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expect not exist error, actual: %v", err)
+	}
+	if _, err := n.ReadFile("docs/big.md"); err == nil {
+		t.Error("file larger than MaxContentFileSize is ok?")
+	}
+	for _, name := range []string{"", "/", "."} {
+		if infos, err := n.ReadDir(name); err != nil || len(infos) != 1 || infos[0].Name() != "docs" {
+			t.Errorf("name: %q, actual: %v, error: %v", name, infos, err)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package goview
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageURL default page URL format of paginators, relative to the current page.
+// The other parameters of the query set with WithQuery are kept.
+const DefaultPageURL = "?page={page}"
+
+// Paginator one page of a slice
+type Paginator struct {
+	items      reflect.Value
+	pageSize   int
+	pageNumber int
+	urlFormat  string
+	query      url.Values
+}
+
+// Paginate paginate the slice or array into pages of pageSize items, for the page number
+// starting at 1. Page numbers out of range are clamped to the first or last page.
+func Paginate(items any, pageSize, page int) (*Paginator, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("ViewEngine paginate invalid page size: %v", pageSize)
+	}
+	v := reflect.ValueOf(items)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Invalid:
+		v = reflect.ValueOf([]any{})
+	default:
+		return nil, fmt.Errorf("ViewEngine paginate items must be a slice or array, got %T", items)
+	}
+	p := &Paginator{
+		items:     v,
+		pageSize:  pageSize,
+		urlFormat: DefaultPageURL,
+	}
+	p.pageNumber = min(max(page, 1), p.TotalPages())
+	return p, nil
+}
+
+// WithURL set the page URL format, where `{page}` is replaced with the page number,
+// such as "/posts/page/{page}"
+func (p *Paginator) WithURL(format string) *Paginator {
+	p.urlFormat = format
+	return p
+}
+
+// WithQuery set the query of the current page, the default page URLs keep its parameters
+// with the page replaced, such as "?page=2&tag=go". The `paginate` function sets the request query.
+func (p *Paginator) WithQuery(query url.Values) *Paginator {
+	p.query = query
+	return p
+}
+
+// PageItems get the items of the page, a slice of the same type as the paginated items
+func (p *Paginator) PageItems() any {
+	start := min(p.Offset(), p.items.Len())
+	end := min(start+p.pageSize, p.items.Len())
+	if p.items.Kind() == reflect.Array {
+		out := reflect.MakeSlice(reflect.SliceOf(p.items.Type().Elem()), end-start, end-start)
+		reflect.Copy(out, p.items.Slice(start, end))
+		return out.Interface()
+	}
+	return p.items.Slice(start, end).Interface()
+}
+
+// Offset get the index of the first item of the page
+func (p *Paginator) Offset() int {
+	return (p.pageNumber - 1) * p.pageSize
+}
+
+// PageNumber get the page number, starting at 1
+func (p *Paginator) PageNumber() int {
+	return p.pageNumber
+}
+
+// PageSize get the maximum number of items per page
+func (p *Paginator) PageSize() int {
+	return p.pageSize
+}
+
+// TotalItems get the number of paginated items
+func (p *Paginator) TotalItems() int {
+	return p.items.Len()
+}
+
+// TotalPages get the number of pages, at least 1
+func (p *Paginator) TotalPages() int {
+	return max((p.items.Len()+p.pageSize-1)/p.pageSize, 1)
+}
+
+// HasPrev check if there is a previous page
+func (p *Paginator) HasPrev() bool {
+	return p.pageNumber > 1
+}
+
+// HasNext check if there is a next page
+func (p *Paginator) HasNext() bool {
+	return p.pageNumber < p.TotalPages()
+}
+
+// Prev get the previous page number, the first page has none and returns 0
+func (p *Paginator) Prev() int {
+	if !p.HasPrev() {
+		return 0
+	}
+	return p.pageNumber - 1
+}
+
+// Next get the next page number, the last page has none and returns 0
+func (p *Paginator) Next() int {
+	if !p.HasNext() {
+		return 0
+	}
+	return p.pageNumber + 1
+}
+
+// Pages get all page numbers
+func (p *Paginator) Pages() []int {
+	pages := make([]int, p.TotalPages())
+	for i := range pages {
+		pages[i] = i + 1
+	}
+	return pages
+}
+
+// URL get the URL of the page number
+func (p *Paginator) URL(page int) string {
+	if p.urlFormat == DefaultPageURL && len(p.query) > 0 {
+		query := make(url.Values, len(p.query)+1)
+		for k, v := range p.query {
+			query[k] = v
+		}
+		query.Set("page", strconv.Itoa(page))
+		return "?" + query.Encode()
+	}
+	return strings.ReplaceAll(p.urlFormat, "{page}", strconv.Itoa(page))
+}
+
+// PrevURL get the URL of the previous page, empty on the first page
+func (p *Paginator) PrevURL() string {
+	if !p.HasPrev() {
+		return ""
+	}
+	return p.URL(p.Prev())
+}
+
+// NextURL get the URL of the next page, empty on the last page
+func (p *Paginator) NextURL() string {
+	if !p.HasNext() {
+		return ""
+	}
+	return p.URL(p.Next())
+}
+
+// paginationTemplate builtin pagination partial, rendered by the `pagination` template function
+var paginationTemplate = template.Must(template.New("pagination").Parse(`<nav class="pagination" aria-label="Pagination"><ul>
+{{- if .HasPrev}}<li class="page-prev"><a href="{{.PrevURL}}" rel="prev">&laquo;</a></li>{{end}}
+{{- range .Pages}}{{if eq . $.PageNumber}}<li class="page-current"><span aria-current="page">{{.}}</span></li>{{else}}<li><a href="{{$.URL .}}">{{.}}</a></li>{{end}}{{end}}
+{{- if .HasNext}}<li class="page-next"><a href="{{.NextURL}}" rel="next">&raquo;</a></li>{{end -}}
+</ul></nav>`))
+
+// Pagination render the builtin pagination links, nothing for a single page
+func (p *Paginator) Pagination() (template.HTML, error) {
+	if p.TotalPages() <= 1 {
+		return "", nil
+	}
+	buf := new(bytes.Buffer)
+	if err := paginationTemplate.Execute(buf, p); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// paginateFunc `paginate` template function, with optional page URL format
+func paginateFunc(items any, pageSize, page int, urlFormat ...string) (*Paginator, error) {
+	p, err := Paginate(items, pageSize, page)
+	if err != nil {
+		return nil, err
+	}
+	if len(urlFormat) > 0 {
+		p.WithURL(urlFormat[0])
+	}
+	return p, nil
+}
@@ -0,0 +1,93 @@
+package goview
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	for _, v := range []struct {
+		Page      int
+		PageItems []string
+		Number    int
+		PrevURL   string
+		NextURL   string
+	}{
+		{Page: 1, PageItems: []string{"a", "b"}, Number: 1, NextURL: "?page=2"},
+		{Page: 2, PageItems: []string{"c", "d"}, Number: 2, PrevURL: "?page=1", NextURL: "?page=3"},
+		{Page: 3, PageItems: []string{"e"}, Number: 3, PrevURL: "?page=2"},
+		{Page: 9, PageItems: []string{"e"}, Number: 3, PrevURL: "?page=2"},
+		{Page: 0, PageItems: []string{"a", "b"}, Number: 1, NextURL: "?page=2"},
+	} {
+		p, err := Paginate(items, 2, v.Page)
+		if err != nil {
+			t.Fatalf("paginate error: %v", err)
+		}
+		if val := p.PageItems(); !reflect.DeepEqual(val, v.PageItems) {
+			t.Errorf("page: %v, actual: %v, expect: %v", v.Page, val, v.PageItems)
+		}
+		if p.PageNumber() != v.Number || p.TotalPages() != 3 || p.TotalItems() != 5 {
+			t.Errorf("page: %v, actual number: %v, total pages: %v, total items: %v", v.Page, p.PageNumber(), p.TotalPages(), p.TotalItems())
+		}
+		if p.PrevURL() != v.PrevURL || p.NextURL() != v.NextURL {
+			t.Errorf("page: %v, actual prev: %q, next: %q", v.Page, p.PrevURL(), p.NextURL())
+		}
+	}
+
+	if p, err := Paginate(nil, 10, 1); err != nil || p.TotalPages() != 1 || p.HasNext() {
+		t.Errorf("paginate nil: %v, %v", p, err)
+	}
+	if _, err := Paginate("abc", 10, 1); err == nil {
+		t.Error("paginate string is ok?")
+	}
+	if _, err := Paginate(items, 0, 1); err == nil {
+		t.Error("paginate zero page size is ok?")
+	}
+}
+
+func TestViewEngine_Paginate(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "paginate", M{"items": []int{1, 2, 3}, "page": 2}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := `<v>[3]<nav class="pagination" aria-label="Pagination"><ul>` +
+		`<li class="page-prev"><a href="/posts/1" rel="prev">&laquo;</a></li>` +
+		`<li><a href="/posts/1">1</a></li>` +
+		`<li class="page-current"><span aria-current="page">2</span></li>` +
+		`</ul></nav></v>`
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
+
+func TestViewEngine_PaginateQuery(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	req := httptest.NewRequest(http.MethodGet, "/posts?tag=go&page=2&sort=new", nil)
+	recorder := httptest.NewRecorder()
+	if err := gv.RenderRequest(recorder, req, http.StatusOK, "paginatequery", M{"items": []int{1, 2, 3, 4, 5}, "page": 2}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	assertRecorder(t, recorder, http.StatusOK, "<v>?page=1&amp;sort=new&amp;tag=go|?page=3&amp;sort=new&amp;tag=go</v>")
+
+	// Without request the default format applies
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "paginatequery", M{"items": []int{1, 2, 3, 4, 5}, "page": 2}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val := buff.String(); val != "<v>?page=1|?page=3</v>" {
+		t.Errorf("actual: %v", val)
+	}
+}
@@ -0,0 +1,125 @@
+package goview
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxIncludeDepth default maximum include and partial nesting depth
+const DefaultMaxIncludeDepth = 100
+
+// renderState state shared by a render and all of its includes and partials
+type renderState struct {
+	mu       sync.Mutex
+	partials map[string]template.HTML
+	stack    []string
+	failed   []string
+	cached   int            //depth of fragments cached across renders being rendered
+	root     *RenderContext //render context of the top-level template
+}
+
+func newRenderState() *renderState {
+	return &renderState{}
+}
+
+// enter push the template on the include stack, failing when it gets deeper than maxDepth
+func (s *renderState) enter(name string, maxDepth int) error {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxIncludeDepth
+	}
+	if len(s.stack) >= maxDepth {
+		return fmt.Errorf("ViewEngine include depth exceeded %v, name:%v, cycle: %v", maxDepth, name, s.cycle(name))
+	}
+	s.stack = append(s.stack, name)
+	return nil
+}
+
+// leave pop the template from the include stack
+func (s *renderState) leave() {
+	s.stack = s.stack[:len(s.stack)-1]
+}
+
+// fail record the include chain of the innermost failing template
+func (s *renderState) fail() {
+	if s.failed == nil {
+		s.failed = append([]string(nil), s.stack...)
+	}
+}
+
+// renderError wrap the error of the render with the include chain, nil without error
+func (s *renderState) renderError(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RenderError{Name: name, Chain: s.failed, Err: err}
+}
+
+// cycle describe the include chain from the previous include of name, or the whole chain without cycle
+func (s *renderState) cycle(name string) string {
+	chain := append(s.stack[:len(s.stack):len(s.stack)], name)
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i] == name {
+			return strings.Join(chain[i:], " -> ")
+		}
+	}
+	return strings.Join(chain, " -> ")
+}
+
+// partial render the template without master layout, with ctx as the only data.
+// Without ctx the partial is rendered with nil data.
+func (e *ViewEngine) partial(name string, ctx []any, state *renderState, opts ...RenderOption) (template.HTML, error) {
+	if len(ctx) > 1 {
+		return "", fmt.Errorf("ViewEngine partial name:%v, error: too many arguments, expect at most one context", name)
+	}
+	var data any
+	if len(ctx) == 1 {
+		data = ctx[0]
+	}
+	buf := new(bytes.Buffer)
+	err := e.executeTemplate(buf, name, data, false, state, opts...)
+	return template.HTML(buf.String()), err
+}
+
+// partialCached render the partial once per name and variants, the ctx is not part of the key.
+// Output is cached for the render, or for Config.PartialCacheTTL across renders in the fragment store,
+// keyed by the render language too.
+func (e *ViewEngine) partialCached(name string, ctx any, variants []any, language string, state *renderState, opts ...RenderOption) (template.HTML, error) {
+	key := partialKey(name, variants)
+	if e.config.PartialCacheTTL > 0 {
+		return e.cachedFragment(partialKeyPrefix+language+"\x00"+key, e.config.PartialCacheTTL, func() (template.HTML, error) {
+			return e.partial(name, []any{ctx}, state, opts...)
+		})
+	}
+
+	state.mu.Lock()
+	out, ok := state.partials[key]
+	state.mu.Unlock()
+	if ok {
+		return out, nil
+	}
+
+	out, err := e.partial(name, []any{ctx}, state, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	state.mu.Lock()
+	if state.partials == nil {
+		state.partials = make(map[string]template.HTML)
+	}
+	state.partials[key] = out
+	state.mu.Unlock()
+	return out, nil
+}
+
+func partialKey(name string, variants []any) string {
+	var key strings.Builder
+	key.WriteString(name)
+	for _, v := range variants {
+		fmt.Fprintf(&key, "\x00%v", v)
+	}
+	return key.String()
+}
@@ -0,0 +1,52 @@
+package goview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestImagesNamespace_QRCode(t *testing.T) {
+	n := &ImagesNamespace{}
+	svg, err := n.QRCode("https://example.com/t/abc", 256)
+	if err != nil {
+		t.Fatalf("qrcode error: %v", err)
+	}
+	// Version 2 code, 25 modules and the quiet zone
+	if s := string(svg); !strings.HasPrefix(s, `<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256" viewBox="0 0 33 33"`) ||
+		!strings.Contains(s, "M4 4h1v1h-1z") || !strings.HasSuffix(s, `"/></svg>`) {
+		t.Errorf("qrcode svg: %v", s)
+	}
+
+	uri, err := n.QRCodePNG("https://example.com/t/abc", 66)
+	if err != nil {
+		t.Fatalf("qrcode png error: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(uri), "data:image/png;base64,"))
+	if err != nil {
+		t.Fatalf("qrcode png data uri: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("qrcode png decode error: %v", err)
+	}
+	// 2 pixels per module: quiet zone white, finder pattern corner black
+	if b := img.Bounds(); b.Dx() != 66 || b.Dy() != 66 {
+		t.Errorf("qrcode png size: %v", b)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("qrcode png quiet zone is black")
+	}
+	if r, _, _, _ := img.At(8, 8).RGBA(); r != 0 {
+		t.Error("qrcode png finder pattern is white")
+	}
+
+	if _, err := n.QRCode("x", 0); err == nil {
+		t.Error("qrcode size 0 is ok?")
+	}
+	if _, err := n.QRCodePNG("x", MaxQRCodeSize+1); err == nil {
+		t.Error("qrcode png size above maximum is ok?")
+	}
+}
@@ -0,0 +1,532 @@
+package goview
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultDataTimeout default timeout of remote data requests
+	DefaultDataTimeout = 10 * time.Second
+	// DefaultDataTTL default time remote data is cached in the fragment store
+	DefaultDataTTL = 5 * time.Minute
+	// DefaultDataMaxBytes default maximum size of remote data responses
+	DefaultDataMaxBytes = 10 << 20
+	// DefaultDataRetryBackoff default wait before the first retry of remote data requests
+	DefaultDataRetryBackoff = 200 * time.Millisecond
+	// DefaultDataBreakerCooldown default time requests to a host fail fast once its circuit breaker opened
+	DefaultDataBreakerCooldown = 30 * time.Second
+
+	dataKeyPrefix = "goview:data:"
+)
+
+// DataOptions options of the remote data functions of the `data` template namespace
+type DataOptions struct {
+	Client     *http.Client  //client of the requests, nil uses a client like http.DefaultClient
+	Timeout    time.Duration //timeout of a fetch including its retries, 0 uses DefaultDataTimeout
+	TTL        time.Duration //time responses are cached in the fragment store, 0 uses DefaultDataTTL, negative disables caching
+	MaxBytes   int64         //maximum response size, 0 uses DefaultDataMaxBytes
+	AllowHosts []string      //hosts templates may fetch from, also checked on redirects, such as "api.example.com" or "*.example.com", empty denies all, "*" allows all
+
+	CacheDir         string        //directory responses are kept in between restarts, expired ones are revalidated with ETag and served stale when the upstream fails
+	MaxStale         time.Duration //time expired responses are served while they are revalidated in the background, 0 revalidates before serving
+	Retries          int           //retries of requests failing with network errors, 429 or 5xx responses
+	RetryBackoff     time.Duration //wait before the first retry, doubled for each retry, 0 uses DefaultDataRetryBackoff
+	BreakerThreshold int           //consecutive failed requests to a host opening its circuit breaker, 0 disables it
+	BreakerCooldown  time.Duration //time requests to a host fail fast once its circuit breaker opened, 0 uses DefaultDataBreakerCooldown
+}
+
+// dataEntry remote data response kept in DataOptions.CacheDir
+type dataEntry struct {
+	URL          string    `json:"url"`
+	Fetched      time.Time `json:"fetched"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+// dataResponse response of a remote data request
+type dataResponse struct {
+	status       int
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// dataBreaker circuit breaker state of a host
+type dataBreaker struct {
+	failures  int
+	openUntil time.Time
+	probing   bool //a half-open probe request is in flight
+}
+
+// SetDataOptions set the options of the remote data functions
+func (e *ViewEngine) SetDataOptions(opts DataOptions) {
+	e.dataMu.Lock()
+	e.dataOptions = opts
+	e.dataBreakers = nil
+	e.dataMu.Unlock()
+}
+
+// InvalidateData delete all cached remote data, in the fragment store and DataOptions.CacheDir
+func (e *ViewEngine) InvalidateData() error {
+	if err := e.fragments.DeletePrefix(dataKeyPrefix); err != nil {
+		return err
+	}
+	if dir := e.getDataOptions().CacheDir; dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if err := os.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *ViewEngine) getDataOptions() DataOptions {
+	e.dataMu.Lock()
+	defer e.dataMu.Unlock()
+	return e.dataOptions
+}
+
+// fetchData get the response body of the URL, cached in the fragment store and DataOptions.CacheDir.
+// Concurrent fetches of the same URL are requested once. Store errors are logged and the URL fetched uncached.
+func (e *ViewEngine) fetchData(rawURL string, header http.Header) ([]byte, error) {
+	opts := e.getDataOptions()
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid URL: %q", rawURL)
+	}
+	if !hostAllowed(u.Hostname(), opts.AllowHosts) {
+		return nil, fmt.Errorf("host not allowed: %v", u.Hostname())
+	}
+
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = DefaultDataTTL
+	}
+	if ttl < 0 {
+		resp, err := e.requestData(opts, u, header, nil)
+		if err != nil {
+			return nil, err
+		}
+		return resp.body, nil
+	}
+
+	h := fnv.New64a()
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%v: %v\n", k, header[k])
+	}
+	key := dataKeyPrefix + rawURL + "\x00" + strconv.FormatUint(h.Sum64(), 16)
+	maxStale := max(opts.MaxStale, 0)
+	revalidate := func() {
+		e.dataGroup.start(key, func() ([]byte, bool, error) {
+			body, _, err := e.loadData(opts, u, header, key, ttl, false)
+			if err != nil {
+				e.logger.Warn("goview: remote data revalidation failed", "url", rawURL, "error", err)
+			}
+			return body, false, err
+		})
+	}
+
+	if body, fetched, ok := e.getData(key); ok {
+		age := time.Since(fetched)
+		if age < ttl {
+			return body, nil
+		}
+		if age < ttl+maxStale {
+			revalidate()
+			return body, nil
+		}
+	}
+	body, stale, err := e.dataGroup.do(key, func() ([]byte, bool, error) {
+		return e.loadData(opts, u, header, key, ttl, true)
+	})
+	if err == nil && stale {
+		revalidate()
+	}
+	return body, err
+}
+
+// loadData get the response from DataOptions.CacheDir or request it, and set it in the fragment store.
+// With allowStale, an expired response within DataOptions.MaxStale is returned as stale, for the caller
+// to revalidate it in the background.
+func (e *ViewEngine) loadData(opts DataOptions, u *url.URL, header http.Header, key string, ttl time.Duration, allowStale bool) ([]byte, bool, error) {
+	rawURL := u.String()
+	maxStale := max(opts.MaxStale, 0)
+	var entry *dataEntry
+	var file string
+	if opts.CacheDir != "" {
+		sum := sha256.Sum256([]byte(key))
+		file = filepath.Join(opts.CacheDir, hex.EncodeToString(sum[:16])+".json")
+		entry = readDataEntry(file, rawURL)
+	}
+	if entry != nil {
+		if age := time.Since(entry.Fetched); age < ttl || (allowStale && age < ttl+maxStale) {
+			e.setData(key, entry.Body, entry.Fetched, ttl+maxStale-age)
+			return entry.Body, age >= ttl, nil
+		}
+	}
+
+	resp, err := e.requestData(opts, u, header, entry)
+	if err != nil {
+		if entry == nil {
+			return nil, false, err
+		}
+		e.logger.Warn("goview: remote data failed, serving stale", "url", rawURL, "fetched", entry.Fetched, "error", err)
+		return entry.Body, false, nil
+	}
+	if resp.status == http.StatusNotModified {
+		resp.body, resp.etag, resp.lastModified = entry.Body, entry.ETag, entry.LastModified
+	}
+	fetched := time.Now()
+	if file != "" {
+		entry = &dataEntry{URL: rawURL, Fetched: fetched, ETag: resp.etag, LastModified: resp.lastModified, Body: resp.body}
+		if err := writeDataEntry(file, entry); err != nil {
+			e.logger.Error("goview: remote data cache write failed", "file", file, "error", err)
+		}
+	}
+	e.setData(key, resp.body, fetched, ttl+maxStale)
+	return resp.body, false, nil
+}
+
+// getData get the remote data and the time it was fetched from the fragment store, logging errors
+func (e *ViewEngine) getData(key string) ([]byte, time.Time, bool) {
+	value, ok, err := e.fragments.Get(key)
+	if err != nil {
+		e.logger.Error("goview: fragment store get failed", "key", key, "error", err)
+		return nil, time.Time{}, false
+	}
+	if !ok || len(value) < 8 {
+		return nil, time.Time{}, false
+	}
+	return value[8:], time.Unix(0, int64(binary.BigEndian.Uint64(value))), true
+}
+
+// setData set the remote data in the fragment store prefixed with the time it was fetched, logging errors
+func (e *ViewEngine) setData(key string, body []byte, fetched time.Time, ttl time.Duration) {
+	value := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(body)), uint64(fetched.UnixNano()))
+	if err := e.fragments.Set(key, append(value, body...), ttl); err != nil {
+		e.logger.Error("goview: fragment store set failed", "key", key, "error", err)
+	}
+}
+
+// readDataEntry read the cached response of the URL, nil if missing or invalid
+func readDataEntry(file string, rawURL string) *dataEntry {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	entry := new(dataEntry)
+	if err := json.Unmarshal(b, entry); err != nil || entry.URL != rawURL {
+		return nil
+	}
+	return entry
+}
+
+// writeDataEntry write the cached response, atomically replacing the file
+func writeDataEntry(file string, entry *dataEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// requestData request the URL with retries and the circuit breaker of its host, revalidating the
+// cached entry with If-None-Match and If-Modified-Since. DataOptions.Timeout bounds all attempts.
+func (e *ViewEngine) requestData(opts DataOptions, u *url.URL, header http.Header, entry *dataEntry) (*dataResponse, error) {
+	host := u.Host
+	if err := e.breakerAllow(opts, host); err != nil {
+		return nil, err
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDataTimeout
+	}
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultDataRetryBackoff
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var resp *dataResponse
+	var err error
+	var retry bool
+	for i := 0; ; i++ {
+		resp, err = e.doRequestData(ctx, opts, u.String(), header, entry)
+		retry = err != nil || resp.status == http.StatusTooManyRequests || resp.status >= 500
+		if !retry || i >= opts.Retries {
+			break
+		}
+		e.logger.Debug("goview: remote data retry", "url", u.String(), "attempt", i+1, "backoff", backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff *= 2
+	}
+	e.breakerRecord(opts, host, retry)
+	if err != nil {
+		return nil, err
+	}
+	if resp.status == http.StatusNotModified && entry != nil {
+		return resp, nil
+	}
+	if resp.status < 200 || resp.status > 299 {
+		return nil, fmt.Errorf("unexpected status: %v %v", resp.status, http.StatusText(resp.status))
+	}
+	return resp, nil
+}
+
+// doRequestData request the URL once
+func (e *ViewEngine) doRequestData(ctx context.Context, opts DataOptions, rawURL string, header http.Header, entry *dataEntry) (*dataResponse, error) {
+	client := dataClient(opts)
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultDataMaxBytes
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	e.logger.Debug("goview: remote data fetched", "url", rawURL, "status", resp.StatusCode, "duration", time.Since(start))
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("response exceeds %v bytes", maxBytes)
+	}
+	return &dataResponse{
+		status:       resp.StatusCode,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		body:         body,
+	}, nil
+}
+
+// breakerAllow fail fast while the circuit breaker of the host is open. Once the cooldown passed the
+// breaker is half-open, a single probe request is let through and the others fail fast until it finished.
+func (e *ViewEngine) breakerAllow(opts DataOptions, host string) error {
+	if opts.BreakerThreshold <= 0 {
+		return nil
+	}
+	e.dataMu.Lock()
+	defer e.dataMu.Unlock()
+	b := e.dataBreakers[host]
+	if b == nil || b.failures < opts.BreakerThreshold {
+		return nil
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return fmt.Errorf("circuit breaker open: %v", host)
+	}
+	b.probing = true
+	return nil
+}
+
+// breakerRecord record the result of a request to the host, opening its circuit breaker
+// after DataOptions.BreakerThreshold consecutive failures, or again after a failed probe
+func (e *ViewEngine) breakerRecord(opts DataOptions, host string, failed bool) {
+	if opts.BreakerThreshold <= 0 {
+		return
+	}
+	e.dataMu.Lock()
+	defer e.dataMu.Unlock()
+	if !failed {
+		delete(e.dataBreakers, host)
+		return
+	}
+	if e.dataBreakers == nil {
+		e.dataBreakers = make(map[string]*dataBreaker)
+	}
+	b := e.dataBreakers[host]
+	if b == nil {
+		b = new(dataBreaker)
+		e.dataBreakers[host] = b
+	}
+	b.probing = false
+	if b.failures++; b.failures >= opts.BreakerThreshold {
+		cooldown := opts.BreakerCooldown
+		if cooldown <= 0 {
+			cooldown = DefaultDataBreakerCooldown
+		}
+		b.openUntil = time.Now().Add(cooldown)
+		e.logger.Warn("goview: remote data circuit breaker open", "host", host, "failures", b.failures, "cooldown", cooldown)
+	}
+}
+
+// dataClient get a copy of the client of the options, checking the hosts of redirects against
+// DataOptions.AllowHosts, so an allowed host can't redirect to a denied one
+func dataClient(opts DataOptions) *http.Client {
+	client := http.Client{}
+	if opts.Client != nil {
+		client = *opts.Client
+	}
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !hostAllowed(req.URL.Hostname(), opts.AllowHosts) {
+			return fmt.Errorf("redirect to host not allowed: %v", req.URL.Hostname())
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &client
+}
+
+// hostAllowed check the host against the allowed hosts, "*.example.com" allows subdomains, "*" all hosts
+func hostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == "*" || host == a || (strings.HasPrefix(a, "*.") && strings.HasSuffix(host, a[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// toHeader convert the optional request headers of the data functions, a map of strings or http.Header
+func toHeader(headers []any) (http.Header, error) {
+	header := make(http.Header)
+	if len(headers) == 0 || headers[0] == nil {
+		return header, nil
+	}
+	switch h := headers[0].(type) {
+	case http.Header:
+		for k, v := range h {
+			header[http.CanonicalHeaderKey(k)] = v
+		}
+	case map[string]string:
+		for k, v := range h {
+			header.Set(k, v)
+		}
+	case map[string]any:
+		for k, v := range h {
+			header.Set(k, fmt.Sprint(v))
+		}
+	case M:
+		for k, v := range h {
+			header.Set(k, fmt.Sprint(v))
+		}
+	default:
+		return nil, fmt.Errorf("invalid headers: %T", headers[0])
+	}
+	return header, nil
+}
+
+// DataNamespace `data` template namespace, remote data fetched with DataOptions
+type DataNamespace struct {
+	e *ViewEngine
+}
+
+// GetJSON fetch and decode the JSON document of the URL, with optional request headers
+func (n *DataNamespace) GetJSON(rawURL string, headers ...any) (any, error) {
+	header, err := toHeader(headers)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine data get json url:%v, error: %v", rawURL, err)
+	}
+	if header.Get("Accept") == "" {
+		header.Set("Accept", "application/json")
+	}
+	body, err := n.e.fetchData(rawURL, header)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine data get json url:%v, error: %v", rawURL, err)
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, fmt.Errorf("ViewEngine data get json url:%v, error: %v", rawURL, err)
+	}
+	return v, nil
+}
+
+// GetCSV fetch and parse the CSV document of the URL with the separator, such as ",", with optional request headers
+func (n *DataNamespace) GetCSV(sep string, rawURL string, headers ...any) ([][]string, error) {
+	comma, size := utf8.DecodeRuneInString(sep)
+	if size == 0 || size != len(sep) {
+		return nil, fmt.Errorf("ViewEngine data get csv url:%v, error: invalid separator: %q", rawURL, sep)
+	}
+	header, err := toHeader(headers)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine data get csv url:%v, error: %v", rawURL, err)
+	}
+	if header.Get("Accept") == "" {
+		header.Set("Accept", "text/csv")
+	}
+	body, err := n.e.fetchData(rawURL, header)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine data get csv url:%v, error: %v", rawURL, err)
+	}
+	r := csv.NewReader(bytes.NewReader(body))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine data get csv url:%v, error: %v", rawURL, err)
+	}
+	return records, nil
+}
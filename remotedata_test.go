@@ -0,0 +1,344 @@
+package goview
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestViewEngine_Data(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		switch r.URL.Path {
+		case "/status.json":
+			if r.Header.Get("Authorization") != "token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"name":"api","tags":["a","b"]}`)
+		case "/prices.csv":
+			fmt.Fprint(w, "apple;1\npear;2\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	gv.SetDataOptions(DataOptions{AllowHosts: []string{"127.0.0.1"}})
+	data := M{
+		"json":    srv.URL + "/status.json",
+		"csv":     srv.URL + "/prices.csv",
+		"headers": map[string]string{"Authorization": "token"},
+	}
+	for i := 0; i < 2; i++ {
+		buff := new(bytes.Buffer)
+		if err := gv.RenderWriter(buff, "data", data); err != nil {
+			t.Fatalf("render error: %v", err)
+		}
+		if val, expect := buff.String(), "<v>api: a b|apple=1,pear=2,</v>"; val != expect {
+			t.Errorf("actual: %q, expect: %q", val, expect)
+		}
+	}
+	if n := atomic.LoadInt32(&hits); n != 2 {
+		t.Errorf("requests: %v, expect 2 with cached responses", n)
+	}
+
+	if err := gv.InvalidateData(); err != nil {
+		t.Fatalf("invalidate error: %v", err)
+	}
+	if err := gv.RenderWriter(new(bytes.Buffer), "data", data); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 4 {
+		t.Errorf("requests: %v, expect 4 after invalidation", n)
+	}
+
+	// Headers are part of the cache key
+	data["headers"] = nil
+	err := gv.RenderWriter(new(bytes.Buffer), "data", data)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expect unauthorized error, actual: %v", err)
+	}
+}
+
+func TestDataNamespace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		fmt.Fprint(w, `"0123456789"`)
+	}))
+	defer srv.Close()
+
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{Timeout: 20 * time.Millisecond, TTL: -1, MaxBytes: 8, AllowHosts: []string{"127.0.0.1"}})
+	n := &DataNamespace{e: gv}
+	if _, err := n.GetJSON(srv.URL); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("expect size error, actual: %v", err)
+	}
+	if _, err := n.GetJSON(srv.URL + "/slow"); err == nil {
+		t.Errorf("expect timeout error")
+	}
+	if _, err := n.GetJSON("file:///etc/passwd"); err == nil {
+		t.Errorf("expect invalid URL error")
+	}
+	if _, err := n.GetCSV(";;", srv.URL); err == nil {
+		t.Errorf("expect invalid separator error")
+	}
+	if _, err := n.GetJSON(srv.URL, 1); err == nil {
+		t.Errorf("expect invalid headers error")
+	}
+
+	gv.SetDataOptions(DataOptions{AllowHosts: []string{"*.example.com"}})
+	if _, err := n.GetJSON(srv.URL); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expect host error, actual: %v", err)
+	}
+	if !hostAllowed("API.example.com", []string{"*.example.com"}) || hostAllowed("example.com.evil", []string{"*.example.com"}) {
+		t.Errorf("host allowed mismatch")
+	}
+
+	// Without allowed hosts nothing is fetched
+	gv.SetDataOptions(DataOptions{})
+	if _, err := n.GetJSON(srv.URL); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expect host error, actual: %v", err)
+	}
+}
+
+func TestDataNamespace_Redirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+			return
+		}
+		if r.URL.Path == "/local" {
+			http.Redirect(w, r, "/ok", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, `1`)
+	}))
+	defer srv.Close()
+
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{TTL: -1, AllowHosts: []string{"127.0.0.1"}})
+	n := &DataNamespace{e: gv}
+	if _, err := n.GetJSON(srv.URL + "/internal"); err == nil || !strings.Contains(err.Error(), "redirect to host not allowed") {
+		t.Errorf("expect redirect error, actual: %v", err)
+	}
+	if val, err := n.GetJSON(srv.URL + "/local"); err != nil || val != float64(1) {
+		t.Errorf("actual: %v, error: %v", val, err)
+	}
+}
+
+func TestDataNamespace_Retry(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `1`)
+	}))
+	defer srv.Close()
+
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{TTL: -1, Retries: 2, RetryBackoff: time.Millisecond, AllowHosts: []string{"*"}})
+	n := &DataNamespace{e: gv}
+	if val, err := n.GetJSON(srv.URL); err != nil || val != float64(1) {
+		t.Errorf("actual: %v, error: %v", val, err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 3 {
+		t.Errorf("requests: %v, expect 3", n)
+	}
+}
+
+func TestDataNamespace_Revalidate(t *testing.T) {
+	var hits, notModified int32
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"v":1}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{TTL: time.Nanosecond, CacheDir: dir, BreakerThreshold: 2, BreakerCooldown: time.Hour, AllowHosts: []string{"127.0.0.1"}})
+	n := &DataNamespace{e: gv}
+	for i := 0; i < 2; i++ {
+		val, err := n.GetJSON(srv.URL)
+		if err != nil || val.(map[string]any)["v"] != float64(1) {
+			t.Fatalf("actual: %v, error: %v", val, err)
+		}
+	}
+	if n := atomic.LoadInt32(&notModified); n != 1 {
+		t.Errorf("not modified responses: %v, expect 1", n)
+	}
+
+	// Failing upstream serves stale data, and opens the breaker after 2 failures
+	fail.Store(true)
+	for i := 0; i < 4; i++ {
+		if val, err := n.GetJSON(srv.URL); err != nil || val.(map[string]any)["v"] != float64(1) {
+			t.Fatalf("actual: %v, error: %v", val, err)
+		}
+	}
+	if n := atomic.LoadInt32(&hits); n != 4 {
+		t.Errorf("requests: %v, expect 4 with open breaker", n)
+	}
+	if _, err := n.GetJSON(srv.URL + "/other"); err == nil || !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Errorf("expect breaker error, actual: %v", err)
+	}
+
+	if err := gv.InvalidateData(); err != nil {
+		t.Fatalf("invalidate error: %v", err)
+	}
+	if _, err := n.GetJSON(srv.URL); err == nil {
+		t.Errorf("expect error without cached data")
+	}
+}
+
+func TestDataNamespace_Concurrent(t *testing.T) {
+	var hits int32
+	var version atomic.Int32
+	version.Store(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintf(w, `{"v":%d}`, version.Load())
+	}))
+	defer srv.Close()
+
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{TTL: 50 * time.Millisecond, MaxStale: time.Hour, AllowHosts: []string{"127.0.0.1"}})
+	n := &DataNamespace{e: gv}
+	get := func() any {
+		val, err := n.GetJSON(srv.URL)
+		if err != nil {
+			t.Errorf("get error: %v", err)
+			return nil
+		}
+		return val.(map[string]any)["v"]
+	}
+
+	// Concurrent misses are requested once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get()
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("requests: %v, expect 1", n)
+	}
+
+	// Expired responses within MaxStale are served while revalidated in the background
+	version.Store(2)
+	time.Sleep(60 * time.Millisecond)
+	if v := get(); v != float64(1) {
+		t.Errorf("stale actual: %v, expect 1", v)
+	}
+	deadline := time.Now().Add(time.Second)
+	for get() != float64(2) {
+		if time.Now().After(deadline) {
+			t.Fatal("stale response not revalidated")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&hits); n != 2 {
+		t.Errorf("requests: %v, expect 2", n)
+	}
+}
+
+func TestDataNamespace_RetryDeadline(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	// Timeout bounds all retries of a fetch
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{TTL: -1, Timeout: 100 * time.Millisecond, Retries: 10, RetryBackoff: 20 * time.Millisecond, AllowHosts: []string{"*"}})
+	start := time.Now()
+	if _, err := (&DataNamespace{e: gv}).GetJSON(srv.URL); err == nil {
+		t.Error("expect error of failing upstream")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("fetch took %v, expect at most the timeout", elapsed)
+	}
+	if n := atomic.LoadInt32(&hits); n > 4 {
+		t.Errorf("requests: %v, expect the retries to stop at the timeout", n)
+	}
+}
+
+func TestDataNamespace_BreakerHalfOpen(t *testing.T) {
+	var hits int32
+	var fail atomic.Bool
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		<-release
+		fmt.Fprint(w, `1`)
+	}))
+	defer srv.Close()
+
+	gv := New(DefaultConfig)
+	gv.SetDataOptions(DataOptions{TTL: -1, BreakerThreshold: 1, BreakerCooldown: 20 * time.Millisecond, AllowHosts: []string{"*"}})
+	n := &DataNamespace{e: gv}
+	fail.Store(true)
+	n.GetJSON(srv.URL)
+	if _, err := n.GetJSON(srv.URL); err == nil || !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Fatalf("expect breaker error, actual: %v", err)
+	}
+
+	// After the cooldown a single probe is let through, the others fail fast until it finished
+	fail.Store(false)
+	time.Sleep(30 * time.Millisecond)
+	probe := make(chan error, 1)
+	go func() {
+		_, err := n.GetJSON(srv.URL)
+		probe <- err
+	}()
+	for atomic.LoadInt32(&hits) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := n.GetJSON(srv.URL); err == nil || !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Errorf("expect breaker error while probing, actual: %v", err)
+	}
+	close(release)
+	if err := <-probe; err != nil {
+		t.Fatalf("probe error: %v", err)
+	}
+	if val, err := n.GetJSON(srv.URL); err != nil || val != float64(1) {
+		t.Errorf("closed breaker actual: %v, error: %v", val, err)
+	}
+}
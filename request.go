@@ -0,0 +1,106 @@
+package goview
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// WithRequest set the request of the render, for the `request` template namespace.
+// RenderRequest, Negotiate, Handler and the framework supports set it.
+func WithRequest(r *http.Request) RenderOption {
+	return func(ctx *RenderContext) {
+		ctx.Request = r
+	}
+}
+
+// RequestNamespace `request` template namespace, read only access to the request of the render.
+// Without request all methods return zero values.
+type RequestNamespace struct {
+	r *http.Request
+}
+
+// Method get the request method, such as "GET"
+func (n *RequestNamespace) Method() string {
+	if n.r == nil {
+		return ""
+	}
+	return n.r.Method
+}
+
+// Host get the request host, with port if present
+func (n *RequestNamespace) Host() string {
+	if n.r == nil {
+		return ""
+	}
+	return n.r.Host
+}
+
+// Scheme get "https" for TLS requests or requests forwarded as https, otherwise "http"
+func (n *RequestNamespace) Scheme() string {
+	if n.r == nil {
+		return ""
+	}
+	if n.r.TLS != nil || strings.EqualFold(n.r.Header.Get("X-Forwarded-Proto"), "https") {
+		return "https"
+	}
+	return "http"
+}
+
+// Path get the unescaped request path
+func (n *RequestNamespace) Path() string {
+	if n.r == nil {
+		return ""
+	}
+	return n.r.URL.Path
+}
+
+// URL get the escaped request path and query, such as "/posts?page=2"
+func (n *RequestNamespace) URL() string {
+	if n.r == nil {
+		return ""
+	}
+	return n.r.URL.RequestURI()
+}
+
+// Query get the first value of the query parameter
+func (n *RequestNamespace) Query(key string) string {
+	if n.r == nil {
+		return ""
+	}
+	return n.r.URL.Query().Get(key)
+}
+
+// QueryValues get all query parameters
+func (n *RequestNamespace) QueryValues() url.Values {
+	if n.r == nil {
+		return url.Values{}
+	}
+	return n.r.URL.Query()
+}
+
+// Header get the first value of the request header
+func (n *RequestNamespace) Header(key string) string {
+	if n.r == nil {
+		return ""
+	}
+	return n.r.Header.Get(key)
+}
+
+// IsPath check if the cleaned request path is the path, ignoring a trailing slash
+func (n *RequestNamespace) IsPath(p string) bool {
+	if n.r == nil {
+		return false
+	}
+	return path.Clean("/"+n.r.URL.Path) == path.Clean("/"+p)
+}
+
+// HasPathPrefix check if the request path is the prefix or below it, such as "/blog" for "/blog/post"
+func (n *RequestNamespace) HasPathPrefix(prefix string) bool {
+	if n.r == nil {
+		return false
+	}
+	p, prefix := path.Clean("/"+n.r.URL.Path), path.Clean("/"+prefix)
+	return p == prefix || prefix == "/" || strings.HasPrefix(p, prefix+"/")
+}
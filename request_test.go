@@ -0,0 +1,64 @@
+package goview
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestViewEngine_Request(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	for _, v := range []struct {
+		Target string
+		Expect string
+	}{
+		{Target: "/blog/post?page=2", Expect: "<v>GET /blog/post 2 active </v>"},
+		{Target: "/blog", Expect: "<v>GET /blog  active home</v>"},
+		{Target: "/blogger", Expect: "<v>GET /blogger   </v>"},
+	} {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, v.Target, nil)
+		if err := gv.RenderRequest(rec, r, http.StatusOK, "request", nil); err != nil {
+			t.Fatalf("render error: %v", err)
+		}
+		if val := rec.Body.String(); val != v.Expect {
+			t.Errorf("target: %v, actual: %v, expect: %v", v.Target, val, v.Expect)
+		}
+	}
+
+	// Renders without request see zero values
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "request", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val, expect := buff.String(), "<v>    </v>"; val != expect {
+		t.Errorf("actual: %q, expect: %q", val, expect)
+	}
+}
+
+func TestRequestNamespace(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "http://example.com/a%20b?x=1&x=2", nil)
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("Accept", "text/html")
+	n := &RequestNamespace{r: r}
+	if n.Scheme() != "https" || n.Host() != "example.com" || n.Method() != http.MethodPost {
+		t.Errorf("scheme: %v, host: %v, method: %v", n.Scheme(), n.Host(), n.Method())
+	}
+	if n.Path() != "/a b" || n.URL() != "/a%20b?x=1&x=2" {
+		t.Errorf("path: %v, url: %v", n.Path(), n.URL())
+	}
+	if val := n.QueryValues()["x"]; len(val) != 2 || n.Query("x") != "1" {
+		t.Errorf("query values: %v", val)
+	}
+	if n.Header("accept") != "text/html" {
+		t.Errorf("header: %v", n.Header("accept"))
+	}
+	if !n.HasPathPrefix("/") {
+		t.Error("root prefix not matched")
+	}
+}
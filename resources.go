@@ -0,0 +1,295 @@
+package goview
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resource asset file, read from the resources file system or derived by the pipeline functions
+type Resource struct {
+	name        string
+	content     []byte
+	mediaType   string
+	sum         [sha256.Size]byte
+	baseURL     string
+	modTime     time.Time
+	fingerprint bool
+}
+
+func newResource(name string, content []byte, baseURL string, modTime time.Time) *Resource {
+	mediaType := mime.TypeByExtension(path.Ext(name))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(content)
+	}
+	return &Resource{
+		name:      name,
+		content:   content,
+		mediaType: mediaType,
+		sum:       sha256.Sum256(content),
+		baseURL:   baseURL,
+		modTime:   modTime,
+	}
+}
+
+// Name get the path of the resource, such as "css/app.min.css"
+func (r *Resource) Name() string {
+	return r.name
+}
+
+// Content get the content of the resource
+func (r *Resource) Content() string {
+	return string(r.content)
+}
+
+// MediaType get the media type of the resource, such as "text/css; charset=utf-8"
+func (r *Resource) MediaType() string {
+	return r.mediaType
+}
+
+// RelPermalink get the URL of the resource under the resources base URL
+func (r *Resource) RelPermalink() string {
+	return r.baseURL + "/" + r.name
+}
+
+// Integrity get the subresource integrity hash, such as "sha256-..."
+func (r *Resource) Integrity() string {
+	return "sha256-" + base64.StdEncoding.EncodeToString(r.sum[:])
+}
+
+// Data get the metadata of the resource: name, mediaType, size and integrity
+func (r *Resource) Data() map[string]any {
+	return map[string]any{
+		"name":      r.name,
+		"mediaType": r.mediaType,
+		"size":      len(r.content),
+		"integrity": r.Integrity(),
+	}
+}
+
+// Resources asset pipeline reading files from a file system. Derived resources are kept in memory
+// and served with the files by ServeHTTP, mount it at the path of the base URL.
+type Resources struct {
+	fsys    fs.FS
+	baseURL string
+
+	mu        sync.RWMutex
+	files     map[string]*Resource
+	published map[string]*Resource
+	compiled  map[string]*Resource
+	sass      SassOptions
+	images    ImageOptions
+}
+
+// NewResources new asset pipeline for the files of fsys, published under the base URL such as "/assets"
+// or "https://cdn.example.com/assets"
+func NewResources(fsys fs.FS, baseURL string) *Resources {
+	return &Resources{
+		fsys:      fsys,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		files:     make(map[string]*Resource),
+		published: make(map[string]*Resource),
+		compiled:  make(map[string]*Resource),
+	}
+}
+
+// errResourceDir error getting a directory of the resources file system
+var errResourceDir = errors.New("is a directory")
+
+// Get get the file resource. Files are cached until their modification time changes.
+func (rs *Resources) Get(name string) (*Resource, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	info, err := fs.Stat(rs.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine resource get name:%v, error: %w", name, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("ViewEngine resource get name:%v, error: %w", name, errResourceDir)
+	}
+	rs.mu.RLock()
+	r, ok := rs.files[name]
+	rs.mu.RUnlock()
+	if ok && r.modTime.Equal(info.ModTime()) {
+		return r, nil
+	}
+	content, err := fs.ReadFile(rs.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine resource get name:%v, error: %w", name, err)
+	}
+	r = newResource(name, content, rs.baseURL, info.ModTime())
+	rs.mu.Lock()
+	rs.files[name] = r
+	rs.mu.Unlock()
+	return r, nil
+}
+
+// Fingerprint copy the resource to a name containing its content hash, such as "css/app.0123456789abcdef.css",
+// served as immutable
+func (rs *Resources) Fingerprint(r *Resource) (*Resource, error) {
+	ext := path.Ext(r.name)
+	name := strings.TrimSuffix(r.name, ext) + "." + hex.EncodeToString(r.sum[:8]) + ext
+	out := newResource(name, r.content, rs.baseURL, r.modTime)
+	out.fingerprint = true
+	return rs.publish(out)
+}
+
+// Minify minify the resource with the minifier, named like "css/app.min.css".
+// Files already named like "app.min.css" are returned as they are.
+func (rs *Resources) Minify(m Minifier, r *Resource) (*Resource, error) {
+	if strings.HasSuffix(strings.TrimSuffix(r.name, path.Ext(r.name)), ".min") && rs.isFile(r.name) {
+		return r, nil
+	}
+	mediaType, _, _ := strings.Cut(r.mediaType, ";")
+	buf := new(bytes.Buffer)
+	if err := m.Minify(mediaType, buf, bytes.NewReader(r.content)); err != nil {
+		return nil, fmt.Errorf("ViewEngine resource minify name:%v, error: %v", r.name, err)
+	}
+	ext := path.Ext(r.name)
+	name := r.name
+	if !strings.HasSuffix(strings.TrimSuffix(name, ext), ".min") {
+		name = strings.TrimSuffix(name, ext) + ".min" + ext
+	}
+	return rs.publish(newResource(name, buf.Bytes(), rs.baseURL, r.modTime))
+}
+
+// Concat join the resources into one named name, separated by newlines
+func (rs *Resources) Concat(name string, resources ...*Resource) (*Resource, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	buf := new(bytes.Buffer)
+	var modTime time.Time
+	for i, r := range resources {
+		if i > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		buf.Write(r.content)
+		if r.modTime.After(modTime) {
+			modTime = r.modTime
+		}
+	}
+	return rs.publish(newResource(name, buf.Bytes(), rs.baseURL, modTime))
+}
+
+// publish store the derived resource for ServeHTTP, derived resources can't shadow the files
+func (rs *Resources) publish(r *Resource) (*Resource, error) {
+	if rs.isFile(r.name) {
+		return nil, fmt.Errorf("ViewEngine resource publish name:%v, error: a resources file has the same name", r.name)
+	}
+	rs.mu.Lock()
+	rs.published[r.name] = r
+	rs.mu.Unlock()
+	return r, nil
+}
+
+func (rs *Resources) isFile(name string) bool {
+	_, err := fs.Stat(rs.fsys, name)
+	return err == nil
+}
+
+// ServeHTTP serve derived resources and files, the path of the base URL is stripped from the request path.
+// Paths outside the base URL and directories are not found.
+func (rs *Resources) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	prefix := rs.baseURL
+	if i := strings.Index(prefix, "://"); i >= 0 {
+		prefix = prefix[i+3:]
+		if j := strings.IndexByte(prefix, '/'); j >= 0 {
+			prefix = prefix[j:]
+		} else {
+			prefix = ""
+		}
+	}
+	if prefix != "" && req.URL.Path != prefix && !strings.HasPrefix(req.URL.Path, prefix+"/") {
+		http.NotFound(w, req)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(req.URL.Path, prefix)), "/")
+
+	rs.mu.RLock()
+	r, ok := rs.published[name]
+	rs.mu.RUnlock()
+	if !ok {
+		var err error
+		if r, err = rs.Get(name); err != nil {
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) || errors.Is(err, errResourceDir) {
+				http.NotFound(w, req)
+			} else {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+	header := w.Header()
+	header.Set("Content-Type", r.mediaType)
+	header.Set("ETag", `"`+hex.EncodeToString(r.sum[:16])+`"`)
+	if r.fingerprint {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	}
+	http.ServeContent(w, req, r.name, r.modTime, bytes.NewReader(r.content))
+}
+
+// SetResources set the asset pipeline of the `resources` template namespace
+func (e *ViewEngine) SetResources(resources *Resources) {
+	e.resources = resources
+}
+
+// Resources get the asset pipeline of the engine, nil if not set
+func (e *ViewEngine) Resources() *Resources {
+	return e.resources
+}
+
+// ResourcesNamespace `resources` template namespace
+type ResourcesNamespace struct {
+	e *ViewEngine
+}
+
+// Get get the file resource, see Resources.Get
+func (n *ResourcesNamespace) Get(name string) (*Resource, error) {
+	rs, err := n.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	return rs.Get(name)
+}
+
+// Fingerprint copy the resource to a name containing its content hash, see Resources.Fingerprint
+func (n *ResourcesNamespace) Fingerprint(r *Resource) (*Resource, error) {
+	rs, err := n.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	return rs.Fingerprint(r)
+}
+
+// Minify minify the resource with the engine minifier, see Resources.Minify
+func (n *ResourcesNamespace) Minify(r *Resource) (*Resource, error) {
+	rs, err := n.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	return rs.Minify(n.e.getMinifier(), r)
+}
+
+// Concat join the resources into one named name, see Resources.Concat
+func (n *ResourcesNamespace) Concat(name string, resources ...*Resource) (*Resource, error) {
+	rs, err := n.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	return rs.Concat(name, resources...)
+}
+
+func (n *ResourcesNamespace) pipeline() (*Resources, error) {
+	if n.e.resources == nil {
+		return nil, errors.New("ViewEngine resources not set, see SetResources")
+	}
+	return n.e.resources, nil
+}
@@ -0,0 +1,144 @@
+package goview
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestResources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/app.css": &fstest.MapFile{Data: []byte("a { color: red; }"), ModTime: time.Unix(1, 0)},
+		"js/a.js":     &fstest.MapFile{Data: []byte("var a = 1;")},
+		"js/b.js":     &fstest.MapFile{Data: []byte("var b = 2;\n")},
+		"js/c.min.js": &fstest.MapFile{Data: []byte("var c=3;")},
+	}
+	rs := NewResources(fsys, "/assets/")
+
+	css, err := rs.Get("/css/../css/app.css")
+	if err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+	if css.Name() != "css/app.css" || css.RelPermalink() != "/assets/css/app.css" || !strings.HasPrefix(css.MediaType(), "text/css") {
+		t.Errorf("name: %v, permalink: %v, media type: %v", css.Name(), css.RelPermalink(), css.MediaType())
+	}
+	if again, _ := rs.Get("css/app.css"); again != css {
+		t.Error("unchanged file not cached")
+	}
+	fsys["css/app.css"] = &fstest.MapFile{Data: []byte("b {}"), ModTime: time.Unix(2, 0)}
+	if changed, _ := rs.Get("css/app.css"); changed.Content() != "b {}" {
+		t.Errorf("changed file content: %v", changed.Content())
+	}
+	if _, err := rs.Get("missing.css"); err == nil {
+		t.Error("get missing file is ok?")
+	}
+
+	min, err := rs.Minify(BuiltinMinifier{}, css)
+	if err != nil {
+		t.Fatalf("minify error: %v", err)
+	}
+	if min.Name() != "css/app.min.css" || min.Content() != "a{color:red}" {
+		t.Errorf("minified name: %v, content: %v", min.Name(), min.Content())
+	}
+	fp, err := rs.Fingerprint(min)
+	if err != nil {
+		t.Fatalf("fingerprint error: %v", err)
+	}
+	if !strings.HasPrefix(fp.Name(), "css/app.min.") || len(fp.Name()) != len("css/app.min.0123456789abcdef.css") {
+		t.Errorf("fingerprint name: %v", fp.Name())
+	}
+	if fp.Integrity() != min.Integrity() || !strings.HasPrefix(fp.Integrity(), "sha256-") {
+		t.Errorf("integrity: %v", fp.Integrity())
+	}
+
+	a, _ := rs.Get("js/a.js")
+	b, _ := rs.Get("js/b.js")
+	bundle, err := rs.Concat("js/bundle.js", a, b)
+	if err != nil {
+		t.Fatalf("concat error: %v", err)
+	}
+	if bundle.Content() != "var a = 1;\nvar b = 2;\n" {
+		t.Errorf("concat content: %q", bundle.Content())
+	}
+
+	// Derived resources can't shadow the files
+	if _, err := rs.Concat("js/a.js", b); err == nil {
+		t.Error("concat shadowing a file is ok?")
+	}
+	c := mustResource(t, rs, "js/c.min.js")
+	if min, err := rs.Minify(BuiltinMinifier{}, c); err != nil || min != c {
+		t.Errorf("minify minified file: %v, error: %v", min, err)
+	}
+
+	for _, v := range []struct {
+		Path   string
+		Status int
+		Body   string
+		Cache  string
+	}{
+		{Path: "/assets/" + fp.Name(), Status: http.StatusOK, Body: "a{color:red}", Cache: "public, max-age=31536000, immutable"},
+		{Path: "/assets/js/bundle.js", Status: http.StatusOK, Body: bundle.Content()},
+		{Path: "/assets/js/a.js", Status: http.StatusOK, Body: "var a = 1;"},
+		{Path: "/assets/missing.js", Status: http.StatusNotFound},
+		{Path: "/assetsjs/a.js", Status: http.StatusNotFound},
+		{Path: "/static/js/a.js", Status: http.StatusNotFound},
+		{Path: "/assets/js", Status: http.StatusNotFound},
+		{Path: "/assets/", Status: http.StatusNotFound},
+		{Path: "/assets", Status: http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		rs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, v.Path, nil))
+		if rec.Code != v.Status {
+			t.Errorf("path: %v, status: %v, expect: %v", v.Path, rec.Code, v.Status)
+			continue
+		}
+		if v.Status == http.StatusOK && (rec.Body.String() != v.Body || rec.Header().Get("Cache-Control") != v.Cache) {
+			t.Errorf("path: %v, body: %q, cache control: %q", v.Path, rec.Body.String(), rec.Header().Get("Cache-Control"))
+		}
+	}
+}
+
+func TestViewEngine_Resources(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "resources", nil); err == nil || !strings.Contains(err.Error(), "resources not set") {
+		t.Errorf("render without resources error: %v", err)
+	}
+
+	gv.SetResources(NewResources(os.DirFS("_examples/test/assets"), "https://cdn.example.com/assets"))
+	buff.Reset()
+	if err := gv.RenderWriter(buff, "resources", nil); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	css, _ := gv.Resources().Minify(BuiltinMinifier{}, mustResource(t, gv.Resources(), "css/app.css"))
+	css, _ = gv.Resources().Fingerprint(css)
+	expect := `<v><link href="` + css.RelPermalink() + `" integrity="` + css.Integrity() + `">` +
+		`<script src="https://cdn.example.com/assets/js/bundle.js"></script></v>`
+	if val := strings.TrimSpace(buff.String()); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+
+	rec := httptest.NewRecorder()
+	gv.Resources().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/css/app.css", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("serve file status: %v", rec.Code)
+	}
+}
+
+func mustResource(t *testing.T, rs *Resources, name string) *Resource {
+	t.Helper()
+	r, err := rs.Get(name)
+	if err != nil {
+		t.Fatalf("get %v error: %v", name, err)
+	}
+	return r
+}
@@ -0,0 +1,168 @@
+package goview
+
+import (
+	"fmt"
+	"html/template"
+	"path"
+	"strings"
+	"text/template/parse"
+)
+
+// builtinFuncs text/template builtin functions, they can only be denied, not removed
+var builtinFuncs = []string{
+	"and", "call", "html", "index", "slice", "js", "len", "not", "or",
+	"print", "printf", "println", "urlquery", "eq", "ge", "gt", "le", "lt", "ne",
+}
+
+// matchFunc check the function name against the patterns, such as "include", "os*" or "os.*".
+// The "ns.*" form matches the namespace function `ns` whose methods are called as `ns.Method`,
+// method patterns such as "os.ReadFile" are checked by checkMethods.
+func matchFunc(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ns, ok := strings.CutSuffix(pattern, ".*"); ok && ns == name {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMethodNamespace check the namespace function name against the namespace of the method patterns,
+// such as "os" for "os.ReadFile" or "os.Read*"
+func matchMethodNamespace(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		ns, method, ok := strings.Cut(pattern, ".")
+		if !ok || method == "*" {
+			continue
+		}
+		if ok, _ := path.Match(ns, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// funcAllowed check the function name against Config.AllowFuncs and Config.DenyFuncs.
+// Namespaces with allowed methods are allowed, their calls are checked by checkMethods.
+func (c Config) funcAllowed(name string) bool {
+	if len(c.AllowFuncs) > 0 && !matchFunc(c.AllowFuncs, name) && !matchMethodNamespace(c.AllowFuncs, name) {
+		return false
+	}
+	return !matchFunc(c.DenyFuncs, name)
+}
+
+// methodRestricted check if the namespace has method patterns in Config.AllowFuncs or Config.DenyFuncs
+func (c Config) methodRestricted(ns string) bool {
+	return matchMethodNamespace(c.AllowFuncs, ns) || matchMethodNamespace(c.DenyFuncs, ns)
+}
+
+// methodAllowed check the namespace method, such as "os.ReadFile", against Config.AllowFuncs and Config.DenyFuncs
+func (c Config) methodAllowed(ns, method string) bool {
+	name := ns + "." + method
+	if len(c.AllowFuncs) > 0 && !matchFunc(c.AllowFuncs, ns) && !matchFunc(c.AllowFuncs, name) {
+		return false
+	}
+	return !matchFunc(c.DenyFuncs, name)
+}
+
+// checkMethods check the namespace method calls of the parsed template against the method patterns.
+// Namespaces with method patterns can only be called as `ns.Method`, so their methods can't be
+// reached through variables.
+func (c Config) checkMethods(tree *parse.Tree) error {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+	var check func(node parse.Node) error
+	checkAll := func(nodes ...parse.Node) error {
+		for _, node := range nodes {
+			if err := check(node); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	denied := func(node parse.Node, format string, args ...any) error {
+		location, _ := tree.ErrorContext(node)
+		return fmt.Errorf("template: %v: "+format, append([]any{location}, args...)...)
+	}
+	check = func(node parse.Node) error {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return nil
+			}
+			return checkAll(n.Nodes...)
+		case *parse.ActionNode:
+			return check(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return nil
+			}
+			for _, cmd := range n.Cmds {
+				if err := check(cmd); err != nil {
+					return err
+				}
+			}
+		case *parse.CommandNode:
+			return checkAll(n.Args...)
+		case *parse.ChainNode:
+			if id, ok := n.Node.(*parse.IdentifierNode); ok && c.methodRestricted(id.Ident) {
+				if !c.methodAllowed(id.Ident, n.Field[0]) {
+					return denied(n, "function %q is not allowed", id.Ident+"."+n.Field[0])
+				}
+				return nil
+			}
+			return check(n.Node)
+		case *parse.IdentifierNode:
+			if c.methodRestricted(n.Ident) {
+				return denied(n, "namespace %q can only be called as %v.Method", n.Ident, n.Ident)
+			}
+		case *parse.IfNode:
+			return checkAll(n.Pipe, n.List, n.ElseList)
+		case *parse.RangeNode:
+			return checkAll(n.Pipe, n.List, n.ElseList)
+		case *parse.WithNode:
+			return checkAll(n.Pipe, n.List, n.ElseList)
+		case *parse.TemplateNode:
+			return check(n.Pipe)
+		}
+		return nil
+	}
+	return check(tree.Root)
+}
+
+// hasMethodPatterns check if Config.AllowFuncs or Config.DenyFuncs have method patterns such as "os.ReadFile"
+func (c Config) hasMethodPatterns() bool {
+	for _, pattern := range append(append([]string(nil), c.AllowFuncs...), c.DenyFuncs...) {
+		if _, method, ok := strings.Cut(pattern, "."); ok && method != "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// sandboxFuncs remove the functions that are not allowed, templates using them fail to parse.
+// Denied builtin functions are replaced with functions failing the execution.
+func sandboxFuncs(funcs template.FuncMap, config Config) {
+	if len(config.AllowFuncs) == 0 && len(config.DenyFuncs) == 0 {
+		return
+	}
+	for name := range funcs {
+		if !config.funcAllowed(name) {
+			delete(funcs, name)
+		}
+	}
+	for _, name := range builtinFuncs {
+		if matchFunc(config.DenyFuncs, name) {
+			funcs[name] = deniedFunc(name)
+		}
+	}
+}
+
+func deniedFunc(name string) func(...any) (string, error) {
+	return func(...any) (string, error) {
+		return "", fmt.Errorf("function %q is not allowed", name)
+	}
+}
@@ -0,0 +1,113 @@
+package goview
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMatchFunc(t *testing.T) {
+	for _, v := range []struct {
+		Patterns []string
+		Name     string
+		Match    bool
+	}{
+		{Patterns: []string{"include"}, Name: "include", Match: true},
+		{Patterns: []string{"include"}, Name: "partial", Match: false},
+		{Patterns: []string{"partial*"}, Name: "partialCached", Match: true},
+		{Patterns: []string{"os.*"}, Name: "os", Match: true},
+		{Patterns: []string{"os.*"}, Name: "osName", Match: false},
+		{Patterns: nil, Name: "os", Match: false},
+	} {
+		if val := matchFunc(v.Patterns, v.Name); val != v.Match {
+			t.Errorf("patterns: %v, name: %v, actual: %v, expect: %v", v.Patterns, v.Name, val, v.Match)
+		}
+	}
+}
+
+func TestViewEngine_SandboxFuncs(t *testing.T) {
+	funcs := template.FuncMap{
+		"echo": func(v string) string {
+			return "$" + v
+		},
+	}
+	for _, v := range []struct {
+		Config Config
+		Name   string
+		Data   M
+		Out    string
+		Err    string
+	}{
+		{Config: Config{DenyFuncs: []string{"echo"}}, Name: "echo.tpl", Err: `function "echo" not defined`},
+		{Config: Config{AllowFuncs: []string{"include"}}, Name: "echo.tpl", Err: `function "echo" not defined`},
+		{Config: Config{AllowFuncs: []string{"include"}}, Name: "include", Data: M{"name": "GoView"}, Out: "<v>IncGoView</v>"},
+		{Config: Config{AllowFuncs: []string{"e*"}}, Name: "echo.tpl", Data: M{"name": "GoView"}, Out: "$GoView"},
+		{Config: Config{DenyFuncs: []string{"call"}}, Name: "sum", Data: M{"sum": func(a, b int) int { return a + b }}, Err: `function "call" is not allowed`},
+	} {
+		config := v.Config
+		config.Root = "_examples/test"
+		config.Extension = ".tpl"
+		config.Master = "layouts/master"
+		config.Funcs = funcs
+		gv := New(config)
+
+		buff := new(bytes.Buffer)
+		err := gv.RenderWriter(buff, v.Name, v.Data)
+		if v.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), v.Err) {
+				t.Errorf("name: %v, actual error: %v, expect: %v", v.Name, err, v.Err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("name: %v, error: %v", v.Name, err)
+			continue
+		}
+		if val := buff.String(); val != v.Out {
+			t.Errorf("actual: %v, expect: %v", val, v.Out)
+		}
+	}
+}
+
+func TestViewEngine_SandboxMethods(t *testing.T) {
+	for _, v := range []struct {
+		Config Config
+		Name   string
+		Out    string
+		Err    string
+	}{
+		{Config: Config{DenyFuncs: []string{"os.ReadFile"}}, Name: "os", Err: `os:1:24: function "os.ReadFile" is not allowed`},
+		{Config: Config{AllowFuncs: []string{"os.ReadDir"}}, Name: "os", Err: `function "os.ReadFile" is not allowed`},
+		{Config: Config{AllowFuncs: []string{"os.Read*"}}, Name: "os", Out: "<v>v1.0.0|a.md:1 </v>"},
+		{Config: Config{DenyFuncs: []string{"os.Write*"}}, Name: "os", Out: "<v>v1.0.0|a.md:1 </v>"},
+		{Config: Config{DenyFuncs: []string{"os.ReadFile"}}, Name: "osvar", Err: `namespace "os" can only be called as os.Method`},
+	} {
+		config := v.Config
+		config.Root = "_examples/test"
+		config.Extension = ".tpl"
+		config.Master = "layouts/master"
+		gv := New(config)
+		gv.SetContentFS(fstest.MapFS{
+			"CHANGELOG.md": {Data: []byte("v1.0.0")},
+			"docs/a.md":    {Data: []byte("a")},
+		})
+
+		buff := new(bytes.Buffer)
+		err := gv.RenderWriter(buff, v.Name, nil)
+		if v.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), v.Err) {
+				t.Errorf("allow: %v, deny: %v, actual error: %v, expect: %v", v.Config.AllowFuncs, v.Config.DenyFuncs, err, v.Err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("allow: %v, deny: %v, error: %v", v.Config.AllowFuncs, v.Config.DenyFuncs, err)
+			continue
+		}
+		if val := buff.String(); val != v.Out {
+			t.Errorf("actual: %v, expect: %v", val, v.Out)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package goview
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSassBinary default dart-sass executable, looked up in PATH
+	DefaultSassBinary = "sass"
+	// DefaultSassTimeout default time a dart-sass compilation may run before it is killed
+	DefaultSassTimeout = 30 * time.Second
+)
+
+// SassOptions options of SCSS compilation with the dart-sass executable
+type SassOptions struct {
+	Binary       string        //dart-sass executable, empty uses DefaultSassBinary
+	IncludePaths []string      //load paths of @use and @import, such as "assets/scss"
+	OutputStyle  string        //"expanded" or "compressed", empty uses the dart-sass default
+	SourceMap    bool          //embed the source map into the css, for development
+	Indented     bool          //compile the indented syntax (.sass) instead of SCSS
+	Timeout      time.Duration //time a compilation may run, zero uses DefaultSassTimeout
+}
+
+// SetSassOptions set the options of ToCSS
+func (rs *Resources) SetSassOptions(opts SassOptions) {
+	rs.mu.Lock()
+	rs.sass = opts
+	rs.mu.Unlock()
+}
+
+// ToCSS compile the SCSS resource to css with the dart-sass executable, named like "scss/app.css".
+// Compiled resources are cached by content and options.
+func (rs *Resources) ToCSS(r *Resource) (*Resource, error) {
+	rs.mu.RLock()
+	opts := rs.sass
+	rs.mu.RUnlock()
+
+	args := []string{"--stdin", "--no-error-css"}
+	for _, p := range opts.IncludePaths {
+		args = append(args, "--load-path="+p)
+	}
+	if opts.OutputStyle != "" {
+		args = append(args, "--style="+opts.OutputStyle)
+	}
+	if opts.SourceMap {
+		args = append(args, "--embed-source-map", "--embed-sources")
+	} else {
+		args = append(args, "--no-source-map")
+	}
+	if opts.Indented || path.Ext(r.name) == ".sass" {
+		args = append(args, "--indented")
+	}
+
+	key := hex.EncodeToString(r.sum[:]) + " " + strings.Join(args, " ")
+	rs.mu.RLock()
+	out, ok := rs.compiled[key]
+	rs.mu.RUnlock()
+	if ok {
+		return out, nil
+	}
+
+	binary := opts.Binary
+	if binary == "" {
+		binary = DefaultSassBinary
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSassTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdin = bytes.NewReader(r.content)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Don't wait for children of a killed compiler holding the output pipes
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%v after %v", ctx.Err(), timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%v: %v", err, msg)
+		}
+		return nil, fmt.Errorf("ViewEngine resource to css name:%v, error: %v", r.name, err)
+	}
+
+	name := strings.TrimSuffix(r.name, path.Ext(r.name)) + ".css"
+	out, err := rs.publish(newResource(name, stdout.Bytes(), rs.baseURL, r.modTime))
+	if err != nil {
+		return nil, err
+	}
+	rs.mu.Lock()
+	rs.compiled[key] = out
+	rs.mu.Unlock()
+	return out, nil
+}
+
+// ToCSS compile the SCSS resource to css, see Resources.ToCSS
+func (n *ResourcesNamespace) ToCSS(r *Resource) (*Resource, error) {
+	rs, err := n.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	return rs.ToCSS(r)
+}
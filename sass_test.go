@@ -0,0 +1,78 @@
+package goview
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// fakeSass write a shell script standing in for dart-sass, it prints its arguments and stdin,
+// and counts its runs in the calls file
+func fakeSass(t *testing.T, script string) (binary, calls string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake sass needs a unix shell")
+	}
+	dir := t.TempDir()
+	binary, calls = filepath.Join(dir, "sass"), filepath.Join(dir, "calls")
+	script = "#!/bin/sh\necho run >> " + calls + "\n" + script
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return binary, calls
+}
+
+func TestResources_ToCSS(t *testing.T) {
+	binary, calls := fakeSass(t, `echo "/* $* */"; cat`)
+	rs := NewResources(fstest.MapFS{
+		"scss/app.scss": &fstest.MapFile{Data: []byte("$c: red; a { color: $c; }")},
+	}, "/assets")
+	rs.SetSassOptions(SassOptions{
+		Binary:       binary,
+		IncludePaths: []string{"node_modules"},
+		OutputStyle:  "compressed",
+	})
+
+	scss, _ := rs.Get("scss/app.scss")
+	css, err := rs.ToCSS(scss)
+	if err != nil {
+		t.Fatalf("to css error: %v", err)
+	}
+	expect := "/* --stdin --no-error-css --load-path=node_modules --style=compressed --no-source-map */\n$c: red; a { color: $c; }"
+	if css.Name() != "scss/app.css" || css.Content() != expect || !strings.HasPrefix(css.MediaType(), "text/css") {
+		t.Errorf("name: %v, media type: %v, content: %q", css.Name(), css.MediaType(), css.Content())
+	}
+	if again, _ := rs.ToCSS(scss); again != css {
+		t.Error("compiled css not cached")
+	}
+	rs.SetSassOptions(SassOptions{Binary: binary, SourceMap: true})
+	if css, _ := rs.ToCSS(scss); !strings.Contains(css.Content(), "--embed-source-map") {
+		t.Errorf("source map content: %q", css.Content())
+	}
+	if data, _ := os.ReadFile(calls); strings.Count(string(data), "run") != 2 {
+		t.Errorf("sass runs: %q", data)
+	}
+
+	failing, _ := fakeSass(t, `echo "Error: expected ;" >&2; exit 65`)
+	rs.SetSassOptions(SassOptions{Binary: failing})
+	if _, err := rs.ToCSS(scss); err == nil || !strings.Contains(err.Error(), "Error: expected ;") {
+		t.Errorf("failing compile error: %v", err)
+	}
+	slow, _ := fakeSass(t, `echo "Compiling" >&2; exec sleep 5`)
+	rs.SetSassOptions(SassOptions{Binary: slow, Timeout: 100 * time.Millisecond})
+	start := time.Now()
+	if _, err := rs.ToCSS(scss); err == nil || !strings.Contains(err.Error(), "deadline exceeded") || !strings.Contains(err.Error(), "Compiling") {
+		t.Errorf("slow compile error: %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("slow compile took %v", d)
+	}
+	rs.SetSassOptions(SassOptions{Binary: filepath.Join(t.TempDir(), "missing")})
+	if _, err := rs.ToCSS(scss); err == nil {
+		t.Error("missing sass binary is ok?")
+	}
+}
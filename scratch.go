@@ -0,0 +1,166 @@
+package goview
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Scratch mutable key value store for templates, created with the `newScratch` template function.
+// The setters return an empty string so they print nothing in templates.
+type Scratch struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// NewScratch new empty scratch
+func NewScratch() *Scratch {
+	return &Scratch{values: make(map[string]any)}
+}
+
+// Set set the value of the key
+func (s *Scratch) Set(key string, value any) string {
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+	return ""
+}
+
+// Get get the value of the key, nil if not set
+func (s *Scratch) Get(key string) any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// Delete delete the key
+func (s *Scratch) Delete(key string) string {
+	s.mu.Lock()
+	delete(s.values, key)
+	s.mu.Unlock()
+	return ""
+}
+
+// Values get a copy of all values
+func (s *Scratch) Values() map[string]any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]any, len(s.values))
+	for k, v := range s.values {
+		out[k] = v
+	}
+	return out
+}
+
+// Add add the value to the value of the key: numbers are summed, strings concatenated
+// and slices appended to. A key that is not set yet is set to the value.
+func (s *Scratch) Add(key string, value any) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.values[key]
+	if !ok || existing == nil {
+		s.values[key] = value
+		return "", nil
+	}
+	sum, err := addValues(existing, value)
+	if err != nil {
+		return "", fmt.Errorf("ViewEngine scratch add key:%v, error: %v", key, err)
+	}
+	s.values[key] = sum
+	return "", nil
+}
+
+// SetInMap set the mapKey of the map stored under the key, creating the map if needed
+func (s *Scratch) SetInMap(key, mapKey string, value any) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.values[key].(map[string]any)
+	if !ok {
+		if s.values[key] != nil {
+			return "", fmt.Errorf("ViewEngine scratch set in map key:%v, error: value is %T, not a map", key, s.values[key])
+		}
+		m = make(map[string]any)
+		s.values[key] = m
+	}
+	m[mapKey] = value
+	return "", nil
+}
+
+// GetSortedMapValues get the values of the map stored under the key, sorted by map key
+func (s *Scratch) GetSortedMapValues(key string) []any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.values[key].(map[string]any)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]any, len(keys))
+	for i, k := range keys {
+		out[i] = m[k]
+	}
+	return out
+}
+
+// growSlice copy the slice into a new slice with n more elements, so appending never writes into
+// the spare capacity of a slice shared with the caller
+func growSlice(v reflect.Value, n int) reflect.Value {
+	out := reflect.MakeSlice(v.Type(), v.Len()+n, v.Len()+n)
+	reflect.Copy(out, v)
+	return out
+}
+
+// addValues sum numbers, concatenate strings or append to slices
+func addValues(a, b any) (any, error) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case av.Kind() == reflect.Slice:
+		if bv.Kind() == reflect.Slice {
+			if av.Type().Elem() == bv.Type().Elem() {
+				out := growSlice(av, bv.Len())
+				reflect.Copy(out.Slice(av.Len(), out.Len()), bv)
+				return out.Interface(), nil
+			}
+			out := toAnySlice(av)
+			return append(out, toAnySlice(bv)...), nil
+		}
+		if b != nil && bv.Type().AssignableTo(av.Type().Elem()) {
+			out := growSlice(av, 1)
+			out.Index(av.Len()).Set(bv)
+			return out.Interface(), nil
+		}
+		return append(toAnySlice(av), b), nil
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return av.String() + bv.String(), nil
+	}
+	if isInt(av) && isInt(bv) {
+		return av.Int() + bv.Int(), nil
+	}
+	x, errA := toFloat(a)
+	y, errB := toFloat(b)
+	if errA != nil || errB != nil {
+		return nil, fmt.Errorf("can't add %T to %T", b, a)
+	}
+	return x + y, nil
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func toAnySlice(v reflect.Value) []any {
+	out := make([]any, v.Len())
+	for i := range out {
+		out[i] = v.Index(i).Interface()
+	}
+	return out
+}
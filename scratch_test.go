@@ -0,0 +1,78 @@
+package goview
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestScratch(t *testing.T) {
+	s := NewScratch()
+	for _, v := range []struct {
+		Key    string
+		Values []any
+		Expect any
+	}{
+		{Key: "int", Values: []any{1, 2, 3}, Expect: int64(6)},
+		{Key: "float", Values: []any{1, 2.5}, Expect: 3.5},
+		{Key: "string", Values: []any{"a", "b"}, Expect: "ab"},
+		{Key: "slice", Values: []any{[]string{"a"}, "b", []string{"c"}}, Expect: []string{"a", "b", "c"}},
+		{Key: "mixed", Values: []any{[]string{"a"}, 1}, Expect: []any{"a", 1}},
+	} {
+		for _, val := range v.Values {
+			if _, err := s.Add(v.Key, val); err != nil {
+				t.Fatalf("key: %v, add error: %v", v.Key, err)
+			}
+		}
+		if val := s.Get(v.Key); !reflect.DeepEqual(val, v.Expect) {
+			t.Errorf("key: %v, actual: %#v, expect: %#v", v.Key, val, v.Expect)
+		}
+	}
+	if _, err := s.Add("int", true); err == nil {
+		t.Error("add bool to int is ok?")
+	}
+
+	// Adding never writes into the spare capacity of the caller's slice
+	base := make([]string, 1, 4)
+	base[0] = "a"
+	s.Set("x", base)
+	s.Set("y", base)
+	s.Add("x", "b")
+	s.Add("y", []string{"c"})
+	if x, y := s.Get("x").([]string), s.Get("y").([]string); x[1] != "b" || y[1] != "c" || base[:2][1] != "" {
+		t.Errorf("shared capacity: x: %v, y: %v, base: %v", x, y, base[:2])
+	}
+
+	s.Delete("int")
+	if val := s.Get("int"); val != nil {
+		t.Errorf("deleted key: %v", val)
+	}
+	if _, err := s.SetInMap("string", "k", 1); err == nil {
+		t.Error("set in map of string is ok?")
+	}
+	s.SetInMap("m", "b", 2)
+	s.SetInMap("m", "a", 1)
+	if val := s.GetSortedMapValues("m"); !reflect.DeepEqual(val, []any{1, 2}) {
+		t.Errorf("sorted map values: %v", val)
+	}
+}
+
+func TestViewEngine_Scratch(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	err := gv.RenderWriter(buff, "scratch", M{"items": []M{
+		{"name": "b", "price": 2, "tag": "x"},
+		{"name": "a", "price": 3, "tag": "y"},
+	}})
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := "<v>5 xy [3 2]</v>"
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
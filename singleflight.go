@@ -0,0 +1,105 @@
+package goview
+
+import (
+	"sync"
+)
+
+// compileCall an in-flight or completed template compilation
+type compileCall struct {
+	wg  sync.WaitGroup
+	tpl viewTemplate
+	err error
+}
+
+// compileGroup deduplicate concurrent compilations of the same template name,
+// so a cold cache entry is parsed once no matter how many goroutines miss it.
+type compileGroup struct {
+	mu    sync.Mutex
+	calls map[string]*compileCall
+}
+
+// do execute fn once for all concurrent callers sharing the same key
+func (g *compileGroup) do(key string, fn func() (viewTemplate, error)) (viewTemplate, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*compileCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.tpl, c.err
+	}
+	c := new(compileCall)
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.tpl, c.err = fn()
+	return c.tpl, c.err
+}
+
+// dataCall an in-flight remote data fetch
+type dataCall struct {
+	wg    sync.WaitGroup
+	body  []byte
+	stale bool
+	err   error
+}
+
+// dataGroup deduplicate concurrent fetches of the same remote data like compileGroup,
+// so an expired response is requested once no matter how many renders miss it.
+type dataGroup struct {
+	mu    sync.Mutex
+	calls map[string]*dataCall
+}
+
+// do execute fn once for all concurrent callers sharing the same key
+func (g *dataGroup) do(key string, fn func() ([]byte, bool, error)) ([]byte, bool, error) {
+	c, ok := g.call(key)
+	if ok {
+		c.wg.Wait()
+		return c.body, c.stale, c.err
+	}
+	g.run(key, c, fn)
+	return c.body, c.stale, c.err
+}
+
+// start execute fn in the background, unless a call of the key is in flight
+func (g *dataGroup) start(key string, fn func() ([]byte, bool, error)) {
+	if c, ok := g.call(key); !ok {
+		go g.run(key, c, fn)
+	}
+}
+
+// call get the in-flight call of the key, or register a new one
+func (g *dataGroup) call(key string) (*dataCall, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[string]*dataCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		return c, true
+	}
+	c := new(dataCall)
+	c.wg.Add(1)
+	g.calls[key] = c
+	return c, false
+}
+
+func (g *dataGroup) run(key string, c *dataCall, fn func() ([]byte, bool, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.body, c.stale, c.err = fn()
+}
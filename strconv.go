@@ -0,0 +1,106 @@
+package goview
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// StrconvNamespace `strconv` template namespace, quoting and parsing of Go literals
+type StrconvNamespace struct{}
+
+var strconvNamespace = &StrconvNamespace{}
+
+// Quote quote the string as a Go string literal, which is also a valid JSON string for printable text
+func (*StrconvNamespace) Quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// QuoteToASCII quote the string as a Go string literal, escaping non-ASCII characters
+func (*StrconvNamespace) QuoteToASCII(s string) string {
+	return strconv.QuoteToASCII(s)
+}
+
+// Unquote unquote the Go string, rune or raw string literal
+func (*StrconvNamespace) Unquote(s string) (string, error) {
+	return strconv.Unquote(s)
+}
+
+// ParseBool parse "1", "t", "true", "0", "f", "false" and their upper case forms
+func (*StrconvNamespace) ParseBool(s string) (bool, error) {
+	return strconv.ParseBool(strings.TrimSpace(s))
+}
+
+// ParseInt parse the integer in the base, 0 detects the base from the prefix such as "0x"
+func (*StrconvNamespace) ParseInt(s string, base int) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(s), base, 64)
+}
+
+// ParseFloat parse the floating point number
+func (*StrconvNamespace) ParseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+// FormatInt format the integer in the base from 2 to 36, such as "ff" for 255 in base 16
+func (*StrconvNamespace) FormatInt(n any, base int) (string, error) {
+	if base < 2 || base > 36 {
+		return "", fmt.Errorf("invalid base: %v", base)
+	}
+	i, err := toInt64(n)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(i, base), nil
+}
+
+// FormatBool format the bool as "true" or "false"
+func (*StrconvNamespace) FormatBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int8:
+		return int64(n), nil
+	case int16:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint:
+		return uintToInt64(uint64(n))
+	case uint8:
+		return int64(n), nil
+	case uint16:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case uint64:
+		return uintToInt64(n)
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(n), 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer: %q", n)
+		}
+		return i, nil
+	}
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid integer: %v", v)
+	}
+	return int64(f), nil
+}
+
+func uintToInt64(n uint64) (int64, error) {
+	if n > math.MaxInt64 {
+		return 0, fmt.Errorf("integer overflows int64: %v", n)
+	}
+	return int64(n), nil
+}
@@ -0,0 +1,69 @@
+package goview
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestStrconvNamespace(t *testing.T) {
+	n := strconvNamespace
+	for _, v := range []struct {
+		In     any
+		Base   int
+		Expect string
+	}{
+		{In: 255, Base: 16, Expect: "ff"},
+		{In: 5, Base: 2, Expect: "101"},
+		{In: int64(-35), Base: 36, Expect: "-z"},
+		{In: uint8(8), Base: 8, Expect: "10"},
+		{In: 10.0, Base: 10, Expect: "10"},
+		{In: "0x1f", Base: 10, Expect: "31"},
+	} {
+		val, err := n.FormatInt(v.In, v.Base)
+		if err != nil {
+			t.Fatalf("format %v error: %v", v.In, err)
+		}
+		if val != v.Expect {
+			t.Errorf("format %v actual: %v, expect: %v", v.In, val, v.Expect)
+		}
+	}
+	for _, in := range []any{1.5, uint64(math.MaxUint64), "abc"} {
+		if _, err := n.FormatInt(in, 10); err == nil {
+			t.Errorf("format %v is ok?", in)
+		}
+	}
+	if _, err := n.FormatInt(1, 37); err == nil {
+		t.Error("format in base 37 is ok?")
+	}
+
+	if val := n.Quote("a\"b\n"); val != `"a\"b\n"` {
+		t.Errorf("quote actual: %v", val)
+	}
+	if val, err := n.Unquote(`"a\tb"`); err != nil || val != "a\tb" {
+		t.Errorf("unquote actual: %q, error: %v", val, err)
+	}
+	if val, err := n.ParseBool(" false "); err != nil || val {
+		t.Errorf("parse bool actual: %v, error: %v", val, err)
+	}
+	if val, err := n.ParseInt("0x10", 0); err != nil || val != 16 {
+		t.Errorf("parse int actual: %v, error: %v", val, err)
+	}
+}
+
+func TestViewEngine_Strconv(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		TextMode:  true,
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "strconv", M{"s": "say \"hi\""}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := "<v>\"say \\\"hi\\\"\" ff yes a\tb</v>"
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
@@ -31,9 +31,12 @@ func Default() *ViewEngine {
 	return New(goview.DefaultConfig)
 }
 
-// Render render template for echo interface
+// Render render template for echo interface, with the request options of the echo context request
 func (e *ViewEngine) Render(w io.Writer, name string, data any, c echo.Context) error {
-	return e.RenderWriter(w, name, data)
+	if c == nil {
+		return e.RenderWriter(w, name, data)
+	}
+	return e.RenderWriter(w, name, data, e.RequestOptions(c.Request())...)
 }
 
 // Render html render for template
@@ -17,11 +17,17 @@ type ViewEngine struct {
 
 // ViewRender view render implement gin interface
 type ViewRender struct {
-	Engine *ViewEngine
-	Name   string
-	Data   any
+	Engine  *ViewEngine
+	Name    string
+	Data    any
+	Request *http.Request
 }
 
+var (
+	_ render.HTMLRender = (*ViewEngine)(nil)
+	_ render.Render     = ViewRender{}
+)
+
 // New new view engine for gin
 func New(config goview.Config) *ViewEngine {
 	return Wrap(goview.New(config))
@@ -39,7 +45,10 @@ func Default() *ViewEngine {
 	return New(goview.DefaultConfig)
 }
 
-// Instance implement gin interface
+// Instance implement gin render.HTMLRender interface, so the engine can be set as `router.HTMLRender`
+// and `ctx.HTML()` renders through goview with master layout, partials and funcs.
+// gin doesn't pass the request to HTMLRender, so the request funcs such as `request`, `cspNonce`
+// and the Accept-Language of `T` are empty, render with HTML or the ginview.HTML helper for them.
 func (e *ViewEngine) Instance(name string, data any) render.Render {
 	return ViewRender{
 		Engine: e,
@@ -50,20 +59,28 @@ func (e *ViewEngine) Instance(name string, data any) render.Render {
 
 // HTML render html
 func (e *ViewEngine) HTML(ctx *gin.Context, code int, name string, data any) {
-	instance := e.Instance(name, data)
-	ctx.Render(code, instance)
+	ctx.Render(code, ViewRender{
+		Engine:  e,
+		Name:    name,
+		Data:    data,
+		Request: ctx.Request,
+	})
 }
 
-// Render (YAML) marshals the given interface object and writes data with custom ContentType.
+// Render render the template and writes it with the engine content type, with the request options
+// of RequestOptions such as the CSP nonce and the Accept-Language.
 func (v ViewRender) Render(w http.ResponseWriter) error {
-	return v.Engine.RenderWriter(w, v.Name, v.Data)
+	if v.Request == nil {
+		return v.Engine.RenderWriter(w, v.Name, v.Data)
+	}
+	return v.Engine.RenderWriter(w, v.Name, v.Data, v.Engine.RequestOptions(v.Request)...)
 }
 
 // WriteContentType write html content type
 func (v ViewRender) WriteContentType(w http.ResponseWriter) {
 	header := w.Header()
 	if val := header["Content-Type"]; len(val) == 0 {
-		header["Content-Type"] = goview.HTMLContentType
+		header["Content-Type"] = v.Engine.ContentType()
 	}
 }
 
@@ -56,9 +56,10 @@ func (e *ViewEngine) ExecuteWriter(w io.Writer, filename string, _ string, bindi
 	if ctx, ok := w.(iris.Context); ok {
 		if v := ctx.Values().Get(templateEngineKey); v != nil {
 			if e, ok := v.(*ViewEngine); ok {
-				return e.ViewEngine.RenderWriter(w, filename, bindingData)
+				return e.ViewEngine.RenderWriter(w, filename, bindingData, e.RequestOptions(ctx.Request())...)
 			}
 		}
+		return e.ViewEngine.RenderWriter(w, filename, bindingData, e.RequestOptions(ctx.Request())...)
 	}
 
 	return e.ViewEngine.RenderWriter(w, filename, bindingData)
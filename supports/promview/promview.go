@@ -0,0 +1,97 @@
+package promview
+
+import (
+	"time"
+
+	"github.com/epikur-io/goview"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector render metrics collector for Prometheus
+type Collector struct {
+	renders  *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	cache    *prometheus.CounterVec
+}
+
+var (
+	_ goview.Metrics       = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// New new collector, metric names are prefixed with the namespace, such as `myapp_goview_renders_total`
+func New(namespace string) *Collector {
+	return NewWithBuckets(namespace, prometheus.DefBuckets)
+}
+
+// NewWithBuckets new collector with custom render duration histogram buckets
+func NewWithBuckets(namespace string, buckets []float64) *Collector {
+	return &Collector{
+		renders: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "goview",
+			Name:      "renders_total",
+			Help:      "Total number of template renders.",
+		}, []string{"template"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "goview",
+			Name:      "render_errors_total",
+			Help:      "Total number of failed template renders.",
+		}, []string{"template"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "goview",
+			Name:      "render_duration_seconds",
+			Help:      "Template render duration in seconds.",
+			Buckets:   buckets,
+		}, []string{"template"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "goview",
+			Name:      "cache_lookups_total",
+			Help:      "Total number of template cache lookups by result (hit or miss).",
+		}, []string{"template", "result"}),
+	}
+}
+
+// Wrap register the collector as metrics observer of the engine
+func Wrap(engine *goview.ViewEngine, c *Collector) *goview.ViewEngine {
+	engine.SetMetrics(c)
+	return engine
+}
+
+// ObserveRender implement goview.Metrics
+func (c *Collector) ObserveRender(name string, duration time.Duration, err error) {
+	c.renders.WithLabelValues(name).Inc()
+	c.duration.WithLabelValues(name).Observe(duration.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(name).Inc()
+	}
+}
+
+// ObserveCache implement goview.Metrics
+func (c *Collector) ObserveCache(name string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.cache.WithLabelValues(name, result).Inc()
+}
+
+// Describe implement prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.renders.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+	c.cache.Describe(ch)
+}
+
+// Collect implement prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.renders.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+	c.cache.Collect(ch)
+}
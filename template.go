@@ -0,0 +1,119 @@
+package goview
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sync"
+	texttemplate "text/template"
+)
+
+// viewTemplate compiled template set, backed by html/template or text/template
+type viewTemplate interface {
+	// parse parse text as the named template of the set
+	parse(name, text string) error
+	// execute execute the named template with the render funcs
+	execute(out io.Writer, name string, data any, funcs template.FuncMap) error
+}
+
+// newViewTemplate new empty template set for the config mode
+func newViewTemplate(name string, config Config, funcs template.FuncMap) (viewTemplate, error) {
+	if config.TextMode {
+		tpl := texttemplate.New(name).Funcs(funcs).Delims(config.Delims.Left, config.Delims.Right)
+		if err := setOptions(tpl.Option, config); err != nil {
+			return nil, err
+		}
+		return &textTemplate{root: tpl, config: config}, nil
+	}
+	tpl := template.New(name).Funcs(funcs).Delims(config.Delims.Left, config.Delims.Right)
+	if err := setOptions(tpl.Option, config); err != nil {
+		return nil, err
+	}
+	return &htmlTemplate{root: tpl, config: config}, nil
+}
+
+// setOptions set the configured template options, Option panics on unknown options
+func setOptions[T any](option func(opt ...string) T, config Config) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("ViewEngine template option error: %v", r)
+		}
+	}()
+	option(config.Options...)
+	if config.StrictVariables {
+		option("missingkey=error")
+	}
+	return nil
+}
+
+// htmlTemplate html/template set, output is contextually escaped.
+// The root is never executed, so it can be cloned, every execution gets its own
+// clone to bind the render funcs without racing with concurrent renders.
+type htmlTemplate struct {
+	root   *template.Template
+	config Config
+	clones sync.Pool
+}
+
+func (t *htmlTemplate) parse(name, text string) error {
+	tmpl := t.root
+	if name != t.root.Name() {
+		tmpl = t.root.New(name)
+	}
+	if _, err := tmpl.Parse(text); err != nil || !t.config.hasMethodPatterns() {
+		return err
+	}
+	for _, v := range t.root.Templates() {
+		if err := t.config.checkMethods(v.Tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *htmlTemplate) execute(out io.Writer, name string, data any, funcs template.FuncMap) error {
+	tpl, ok := t.clones.Get().(*template.Template)
+	if !ok {
+		var err error
+		if tpl, err = t.root.Clone(); err != nil {
+			return err
+		}
+	}
+	defer t.clones.Put(tpl)
+	return tpl.Funcs(funcs).ExecuteTemplate(out, name, data)
+}
+
+// textTemplate text/template set, output is not escaped
+type textTemplate struct {
+	root   *texttemplate.Template
+	config Config
+	clones sync.Pool
+}
+
+func (t *textTemplate) parse(name, text string) error {
+	tmpl := t.root
+	if name != t.root.Name() {
+		tmpl = t.root.New(name)
+	}
+	if _, err := tmpl.Parse(text); err != nil || !t.config.hasMethodPatterns() {
+		return err
+	}
+	for _, v := range t.root.Templates() {
+		if err := t.config.checkMethods(v.Tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *textTemplate) execute(out io.Writer, name string, data any, funcs template.FuncMap) error {
+	tpl, ok := t.clones.Get().(*texttemplate.Template)
+	if !ok {
+		var err error
+		if tpl, err = t.root.Clone(); err != nil {
+			return err
+		}
+	}
+	defer t.clones.Put(tpl)
+	return tpl.Funcs(funcs).ExecuteTemplate(out, name, data)
+}
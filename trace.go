@@ -0,0 +1,215 @@
+package goview
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// TraceHeader response header holding the trace ID with Config.Trace, see TraceHandler
+	TraceHeader = "X-Goview-Trace"
+	// DefaultTraceHistory number of recent traces kept by the engine
+	DefaultTraceHistory = 50
+)
+
+// TraceSpan execution of a template, include or partial
+type TraceSpan struct {
+	Name     string        `json:"name"`
+	Depth    int           `json:"depth"`    //include depth, 0 for the rendered template
+	Start    time.Duration `json:"start"`    //offset from the start of the render
+	Duration time.Duration `json:"duration"` //including nested templates
+	Bytes    int           `json:"bytes"`    //output including nested templates
+	Error    string        `json:"error,omitempty"`
+}
+
+// Trace executed templates of a render, in execution order
+type Trace struct {
+	ID     string    `json:"id"`
+	Name   string    `json:"name"`
+	Method string    `json:"method,omitempty"`
+	URL    string    `json:"url,omitempty"`
+	Time   time.Time `json:"time"`
+
+	mu    sync.Mutex
+	spans []TraceSpan
+}
+
+// NewTrace new trace of the render of the template
+func NewTrace(name string) *Trace {
+	// crypto/rand.Read doesn't fail since Go 1.24
+	id, _ := NewUUIDv7()
+	return &Trace{ID: id.String(), Name: name, Time: time.Now()}
+}
+
+// WithTrace record the executed templates of the render in t
+func WithTrace(t *Trace) RenderOption {
+	return func(ctx *RenderContext) {
+		ctx.Trace = t
+	}
+}
+
+// Spans get the recorded spans in execution order
+func (t *Trace) Spans() []TraceSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TraceSpan(nil), t.spans...)
+}
+
+// Duration get the duration of the render
+func (t *Trace) Duration() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var d time.Duration
+	for _, s := range t.spans {
+		d = max(d, s.Start+s.Duration)
+	}
+	return d
+}
+
+// MarshalJSON encode the trace with its spans
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	type trace Trace
+	return json.Marshal(struct {
+		*trace
+		Duration time.Duration `json:"duration"`
+		Spans    []TraceSpan   `json:"spans"`
+	}{(*trace)(t), t.Duration(), t.Spans()})
+}
+
+// begin start the span of the template, returning its index
+func (t *Trace) begin(name string, depth int, start time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, TraceSpan{Name: name, Depth: depth, Start: start.Sub(t.Time)})
+	return len(t.spans) - 1
+}
+
+// end finish the span with the output size and error
+func (t *Trace) end(i int, start time.Time, bytes int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans[i].Duration = time.Since(start)
+	t.spans[i].Bytes = bytes
+	if err != nil {
+		t.spans[i].Error = err.Error()
+	}
+}
+
+// countWriter writer counting the written bytes
+type countWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// traceHistory recent traces, oldest first
+type traceHistory struct {
+	mu     sync.Mutex
+	traces []*Trace
+}
+
+func (h *traceHistory) add(t *Trace) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.traces) >= DefaultTraceHistory {
+		h.traces = append(h.traces[:0], h.traces[1:]...)
+	}
+	h.traces = append(h.traces, t)
+}
+
+// Traces get the recent traces recorded with Config.Trace, newest first
+func (e *ViewEngine) Traces() []*Trace {
+	e.traces.mu.Lock()
+	defer e.traces.mu.Unlock()
+	out := make([]*Trace, len(e.traces.traces))
+	for i, t := range e.traces.traces {
+		out[len(out)-1-i] = t
+	}
+	return out
+}
+
+// Trace get the recent trace by ID, nil if not found
+func (e *ViewEngine) Trace(id string) *Trace {
+	for _, t := range e.Traces() {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+var traceTemplate = template.Must(template.New("trace").Funcs(template.FuncMap{
+	"percent": func(d, total time.Duration) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return float64(d) * 100 / float64(total)
+	},
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>goview traces</title>
+<style>
+body{margin:24px 32px;font:14px/1.5 system-ui,sans-serif;color:#222}
+table{border-collapse:collapse;width:100%;font:13px/1.6 ui-monospace,monospace}
+th,td{padding:2px 8px;text-align:left;border-bottom:1px solid #eee;white-space:nowrap}
+td.bar{width:50%;position:relative}
+td.bar div{position:absolute;top:4px;bottom:4px;background:#4c8bf5;min-width:1px}
+tr.e td{color:#b3261e}
+tr.e td.bar div{background:#b3261e}
+</style></head><body>
+{{with .Trace}}<p><a href="?">&larr; traces</a></p>
+<h1>{{.Name}}</h1><p>{{.Method}} {{.URL}} &middot; {{.Time.Format "2006-01-02 15:04:05.000"}} &middot; {{.Duration}}</p>
+{{$total := .Duration}}<table><tr><th>template</th><th>duration</th><th>bytes</th><th></th></tr>
+{{range .Spans}}<tr{{if .Error}} class="e" title="{{.Error}}"{{end}}><td style="padding-left:{{.Depth}}em">{{.Name}}</td><td>{{.Duration}}</td><td>{{.Bytes}}</td>
+<td class="bar"><div style="left:{{percent .Start $total | printf "%.2f"}}%;width:{{percent .Duration $total | printf "%.2f"}}%"></div></td></tr>
+{{end}}</table>
+{{else}}<h1>goview traces</h1><table><tr><th>time</th><th>template</th><th>request</th><th>duration</th></tr>
+{{range .Traces}}<tr><td><a href="?id={{.ID}}">{{.Time.Format "15:04:05.000"}}</a></td><td>{{.Name}}</td><td>{{.Method}} {{.URL}}</td><td>{{.Duration}}</td></tr>
+{{end}}</table>{{end}}
+</body></html>
+`))
+
+// TraceHandler http.Handler of the recent traces recorded with Config.Trace, a single trace with the ?id= query.
+// It answers HTML with a flame view, or JSON for "Accept: application/json". Mount it in development only.
+func (e *ViewEngine) TraceHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page struct {
+			Trace  *Trace
+			Traces []*Trace
+		}
+		if id := r.URL.Query().Get("id"); id != "" {
+			if page.Trace = e.Trace(id); page.Trace == nil {
+				http.NotFound(w, r)
+				return
+			}
+		} else {
+			page.Traces = e.Traces()
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if NegotiateFormat(r.Header.Get("Accept"), "text/html", "application/json") == "application/json" {
+			var v any = page.Traces
+			if page.Trace != nil {
+				v = page.Trace
+			}
+			if err := writeData(w, http.StatusOK, JSONContentType, func() ([]byte, error) {
+				return json.Marshal(v)
+			}); err != nil {
+				e.logger.Error("goview: trace handler failed", "error", err)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := traceTemplate.Execute(w, page); err != nil {
+			e.logger.Error("goview: trace handler failed", "error", err)
+		}
+	})
+}
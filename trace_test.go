@@ -0,0 +1,70 @@
+package goview
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestViewEngine_Trace(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		Trace:     true,
+	})
+	rec := httptest.NewRecorder()
+	gv.Handler("include", func(*http.Request) any { return M{"name": "x"} }).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/inc?a=1", nil))
+	if val, expect := rec.Body.String(), "<v>Incx</v>"; val != expect {
+		t.Fatalf("actual: %q, expect: %q", val, expect)
+	}
+	id := rec.Header().Get(TraceHeader)
+	trace := gv.Trace(id)
+	if trace == nil || trace.Name != "include" || trace.URL != "/inc?a=1" {
+		t.Fatalf("trace %q: %+v", id, trace)
+	}
+	spans := trace.Spans()
+	if len(spans) != 2 || spans[0].Name != "include" || spans[0].Depth != 0 || spans[1].Name != "widgets/inc" || spans[1].Depth != 1 {
+		t.Fatalf("spans: %+v", spans)
+	}
+	if spans[0].Bytes != 11 || spans[1].Bytes != 4 || spans[1].Start < spans[0].Start || spans[0].Duration < spans[1].Duration {
+		t.Errorf("spans: %+v", spans)
+	}
+
+	rec = httptest.NewRecorder()
+	gv.TraceHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?id="+id, nil))
+	if body := rec.Body.String(); !strings.Contains(body, "widgets/inc") || !strings.Contains(body, "GET /inc?a=1") {
+		t.Errorf("trace page: %v", body)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	gv.TraceHandler().ServeHTTP(rec, r)
+	var traces []struct {
+		ID    string
+		Spans []TraceSpan
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &traces); err != nil || len(traces) != 1 || traces[0].ID != id || len(traces[0].Spans) != 2 {
+		t.Errorf("traces: %+v, error: %v", traces, err)
+	}
+
+	rec = httptest.NewRecorder()
+	gv.TraceHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?id=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status: %v", rec.Code)
+	}
+}
+
+func TestTraceHistory(t *testing.T) {
+	gv := New(DefaultConfig)
+	for i := 0; i < DefaultTraceHistory+5; i++ {
+		gv.traces.add(NewTrace("t"))
+	}
+	traces := gv.Traces()
+	if len(traces) != DefaultTraceHistory || traces[0].ID <= traces[1].ID {
+		t.Errorf("traces: %v, expect newest first", len(traces))
+	}
+}
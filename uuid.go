@@ -0,0 +1,136 @@
+package goview
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UUID RFC 9562 universally unique identifier
+type UUID [16]byte
+
+// String format the UUID as "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// Version get the version of the UUID, such as 4 or 7
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// NewUUID new random version 4 UUID
+func NewUUID() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u, nil
+}
+
+var uuidV7 struct {
+	mu     sync.Mutex
+	lastMs int64
+	seq    uint16
+}
+
+// NewUUIDv7 new version 7 UUID, ordered by creation time. UUIDs of the same millisecond
+// are ordered by a 12 bit counter starting at a random value.
+func NewUUIDv7() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, err
+	}
+	uuidV7.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= uuidV7.lastMs {
+		uuidV7.seq++
+		if uuidV7.seq > 0x0fff {
+			// Counter overflow, borrow the next millisecond
+			uuidV7.lastMs++
+			uuidV7.seq = 0
+		}
+		ms = uuidV7.lastMs
+	} else {
+		uuidV7.lastMs = ms
+		uuidV7.seq = binary.BigEndian.Uint16(u[6:8]) & 0x07ff
+	}
+	seq := uuidV7.seq
+	uuidV7.mu.Unlock()
+
+	u[0], u[1], u[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	u[3], u[4], u[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	u[6] = 0x70 | byte(seq>>8)
+	u[7] = byte(seq)
+	u[8] = u[8]&0x3f | 0x80
+	return u, nil
+}
+
+// ParseUUID parse the UUID in canonical, braced, "urn:uuid:" or 32 hex digits form
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	in := s
+	if len(in) == 45 && strings.EqualFold(in[:9], "urn:uuid:") {
+		in = in[9:]
+	} else if len(in) == 38 && in[0] == '{' && in[37] == '}' {
+		in = in[1:37]
+	}
+	switch len(in) {
+	case 36:
+		if in[8] != '-' || in[13] != '-' || in[18] != '-' || in[23] != '-' {
+			return u, fmt.Errorf("invalid UUID: %q", s)
+		}
+		in = in[0:8] + in[9:13] + in[14:18] + in[19:23] + in[24:]
+	case 32:
+	default:
+		return u, fmt.Errorf("invalid UUID: %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(in)); err != nil {
+		return u, fmt.Errorf("invalid UUID: %q", s)
+	}
+	return u, nil
+}
+
+// UUIDNamespace `uuid` template namespace
+type UUIDNamespace struct{}
+
+var uuidNamespace = &UUIDNamespace{}
+
+// New new random version 4 UUID string
+func (*UUIDNamespace) New() (string, error) {
+	u, err := NewUUID()
+	return u.String(), err
+}
+
+// NewV7 new time ordered version 7 UUID string
+func (*UUIDNamespace) NewV7() (string, error) {
+	u, err := NewUUIDv7()
+	return u.String(), err
+}
+
+// Parse parse the UUID, see ParseUUID
+func (*UUIDNamespace) Parse(s string) (UUID, error) {
+	return ParseUUID(s)
+}
+
+// IsValid check if the string is a UUID, see ParseUUID
+func (*UUIDNamespace) IsValid(s string) bool {
+	_, err := ParseUUID(s)
+	return err == nil
+}
@@ -0,0 +1,70 @@
+package goview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	u, err := NewUUID()
+	if err != nil {
+		t.Fatalf("new uuid error: %v", err)
+	}
+	if u.Version() != 4 || u[8]&0xc0 != 0x80 {
+		t.Errorf("uuid version: %v, variant: %x", u.Version(), u[8])
+	}
+
+	var last string
+	for i := 0; i < 10000; i++ {
+		u, err := NewUUIDv7()
+		if err != nil {
+			t.Fatalf("new uuid v7 error: %v", err)
+		}
+		if u.Version() != 7 {
+			t.Fatalf("uuid v7 version: %v", u.Version())
+		}
+		if s := u.String(); s <= last {
+			t.Fatalf("uuid v7 not ordered: %v <= %v", s, last)
+		} else {
+			last = s
+		}
+	}
+
+	expect := "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+	for _, in := range []string{
+		expect,
+		"F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6",
+		"{f81d4fae-7dec-11d0-a765-00a0c91e6bf6}",
+		"urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+		"f81d4fae7dec11d0a76500a0c91e6bf6",
+	} {
+		u, err := ParseUUID(in)
+		if err != nil {
+			t.Fatalf("parse %v error: %v", in, err)
+		}
+		if u.String() != expect {
+			t.Errorf("parse %v actual: %v, expect: %v", in, u, expect)
+		}
+	}
+	for _, in := range []string{"", "f81d4fae-7dec-11d0-a765_00a0c91e6bf6", "g81d4fae7dec11d0a76500a0c91e6bf6"} {
+		if _, err := ParseUUID(in); err == nil {
+			t.Errorf("parse %v is ok?", in)
+		}
+	}
+}
+
+func TestViewEngine_UUID(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	if err := gv.RenderWriter(buff, "uuid", M{"id": "{F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6}"}); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	expect := "<v>f81d4fae-7dec-11d0-a765-00a0c91e6bf6 v1 false 36</v>"
+	if val := buff.String(); val != expect {
+		t.Errorf("actual: %v, expect: %v", val, expect)
+	}
+}
@@ -0,0 +1,101 @@
+package goview
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError parse error of a template, see ValidateAll
+type ValidationError struct {
+	Name string //template name, such as "layouts/master"
+	File string //template file, such as "views/layouts/master.html"
+	Line int    //line of the error, 0 if unknown
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%v:%v: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", e.File, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// TemplateNames get the names of the templates with Config.Extension under Config.Root, sorted
+func (e *ViewEngine) TemplateNames() ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(e.config.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, e.config.Extension) {
+			return nil
+		}
+		rel, err := filepath.Rel(e.config.Root, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(strings.TrimSuffix(rel, e.config.Extension)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ViewEngine templates root:%v, error: %v", e.config.Root, err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ValidateAll parse every template under Config.Root with the engine funcs, and return all parse errors
+// as *ValidationError, such as undefined or denied functions and unclosed actions. Nothing is executed.
+func (e *ViewEngine) ValidateAll() []error {
+	names, err := e.TemplateNames()
+	if err != nil {
+		return []error{err}
+	}
+	funcs := e.newRenderContext("", nil, false, newRenderState(), nil).Funcs
+	var errs []error
+	for _, name := range names {
+		if err := e.validate(name, funcs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validate parse the template on its own
+func (e *ViewEngine) validate(name string, funcs template.FuncMap) error {
+	verr := &ValidationError{
+		Name: name,
+		File: filepath.Join(e.config.Root, filepath.FromSlash(name)+e.config.Extension),
+	}
+	content, err := e.fileHandler(e.config, name)
+	if err != nil {
+		verr.Err = err
+		return verr
+	}
+	tpl, err := newViewTemplate(name, e.config, funcs)
+	if err == nil {
+		err = tpl.parse(name, content)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// Drop the "template: name:line:" prefix, the location is part of the error
+	msg := err.Error()
+	if loc := errorLocation.FindStringSubmatchIndex(msg); loc != nil && loc[0] == 0 {
+		verr.Line, _ = strconv.Atoi(msg[loc[4]:loc[5]])
+		msg = strings.TrimSpace(msg[loc[1]:])
+	}
+	verr.Err = errors.New(msg)
+	return verr
+}
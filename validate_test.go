@@ -0,0 +1,56 @@
+package goview
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestViewEngine_ValidateAll(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/validate",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		Funcs:     template.FuncMap{"upper": strings.ToUpper},
+	})
+	names, err := gv.TemplateNames()
+	if err != nil || strings.Join(names, " ") != "index layouts/master widgets/card widgets/missing" {
+		t.Fatalf("names: %v, error: %v", names, err)
+	}
+
+	errs := gv.ValidateAll()
+	if len(errs) != 2 {
+		t.Fatalf("errors: %v", errs)
+	}
+	for i, v := range []struct {
+		Name   string
+		Line   int
+		Expect string
+	}{
+		{Name: "widgets/card", Line: 4, Expect: "_examples/validate/widgets/card.tpl:4: unexpected EOF"},
+		{Name: "widgets/missing", Line: 2, Expect: `_examples/validate/widgets/missing.tpl:2: function "missing" not defined`},
+	} {
+		var verr *ValidationError
+		if !errors.As(errs[i], &verr) || verr.Name != v.Name || verr.Line != v.Line || verr.Error() != v.Expect {
+			t.Errorf("actual: %v, expect: %v", errs[i], v.Expect)
+		}
+	}
+
+	// Denied functions fail validation
+	gv = New(Config{
+		Root:      "_examples/validate",
+		Extension: ".tpl",
+		Funcs:     template.FuncMap{"upper": strings.ToUpper, "missing": strings.ToUpper},
+		DenyFuncs: []string{"humanize.*"},
+	})
+	errs = gv.ValidateAll()
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), `function "humanize" not defined`) {
+		t.Errorf("errors: %v", errs)
+	}
+
+	gv = New(Config{Root: "_examples/missing", Extension: ".tpl"})
+	if errs := gv.ValidateAll(); len(errs) != 1 {
+		t.Errorf("errors: %v", errs)
+	}
+}
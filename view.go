@@ -5,17 +5,23 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
 // HTMLContentType const templateEngineKey = "httpx_templateEngine"
 var HTMLContentType = []string{"text/html; charset=utf-8"}
 
+// TextContentType content type for text mode
+var TextContentType = []string{"text/plain; charset=utf-8"}
+
 // DefaultConfig default config
 var DefaultConfig = Config{
 	Root:         "views",
@@ -30,20 +36,47 @@ var DefaultConfig = Config{
 // ViewEngine view template engine
 type ViewEngine struct {
 	config      Config
-	tplMap      map[string]*template.Template
+	tplMap      map[string]viewTemplate
 	tplMutex    sync.RWMutex
 	fileHandler FileHandler
+
+	compileGroup compileGroup
+	metrics      Metrics
+	logger       *slog.Logger
+	fragments    FragmentStore
+	i18n         *I18n
+	minifier     Minifier
+	resources    *Resources
+	dataMu       sync.Mutex
+	dataOptions  DataOptions
+	dataBreakers map[string]*dataBreaker
+	dataGroup    dataGroup
+	contentFS    fs.FS
+	traces       traceHistory
 }
 
 // Config configuration options
 type Config struct {
-	Root         string           //view root
-	Extension    string           //template extension
-	Master       string           //template master
-	Partials     []string         //template partial, such as head, foot
-	Funcs        template.FuncMap //template functions
-	DisableCache bool             //disable cache, debug mode
-	Delims       Delims           //delimeters
+	Root            string            //view root
+	Extension       string            //template extension
+	Master          string            //template master
+	Partials        []string          //template partial, such as head, foot
+	Funcs           template.FuncMap  //template functions
+	FuncDocs        map[string]string //docs of Funcs by function name or "ns.Method", see FuncReference
+	DisableCache    bool              //disable cache, debug mode
+	Delims          Delims            //delimeters
+	SlowRender      time.Duration     //log renders slower than this as warning, 0 disables
+	Options         []string          //template options, such as "missingkey=zero"
+	StrictVariables bool              //fail on missing map keys, same as option "missingkey=error"
+	PartialCacheTTL time.Duration     //share partialCached output between renders for this long, 0 caches per render, only safe for partials not depending on the request
+	MaxIncludeDepth int               //maximum include and partial nesting depth, 0 uses DefaultMaxIncludeDepth
+	AllowFuncs      []string          //function name patterns templates may use, empty allows all, such as "include", "os*", "os.*" or "os.ReadFile"
+	DenyFuncs       []string          //function name patterns templates may not use, also denies builtin functions such as "call"
+	TextMode        bool              //use text/template without HTML escaping, for plain text output
+	ETag            bool              //set ETag of rendered body and answer 304 Not Modified, see RenderRequest
+	Minify          bool              //minify rendered HTML with the engine minifier, see SetMinifier
+	Debug           bool              //write a developer error page with template source when Handler rendering fails, development only
+	Trace           bool              //record template execution traces of Handler and RenderRequest, see TraceHandler, development only
 }
 
 // M map interface for data
@@ -56,11 +89,16 @@ type Delims struct {
 }
 
 type RenderContext struct {
-	Name      string
-	Funcs     template.FuncMap
-	UseMaster bool
-	Config    Config
-	Data      any
+	Name         string
+	Funcs        template.FuncMap
+	UseMaster    bool
+	Config       Config
+	Data         any
+	LastModified time.Time
+	CSPNonce     string
+	Language     string
+	Request      *http.Request
+	Trace        *Trace
 }
 
 type RenderOption func(ctx *RenderContext)
@@ -72,9 +110,11 @@ type FileHandler func(config Config, tplFile string) (content string, err error)
 func New(config Config) *ViewEngine {
 	return &ViewEngine{
 		config:      config,
-		tplMap:      make(map[string]*template.Template),
+		tplMap:      make(map[string]viewTemplate),
 		tplMutex:    sync.RWMutex{},
 		fileHandler: DefaultFileHandler(),
+		logger:      slog.New(slog.DiscardHandler),
+		fragments:   NewMemoryFragmentStore(),
 	}
 }
 
@@ -87,32 +127,130 @@ func Default() *ViewEngine {
 func (e *ViewEngine) Render(w http.ResponseWriter, statusCode int, name string, data any, opts ...RenderOption) error {
 	header := w.Header()
 	if val := header["Content-Type"]; len(val) == 0 {
-		header["Content-Type"] = HTMLContentType
+		header["Content-Type"] = e.ContentType()
 	}
 	w.WriteHeader(statusCode)
-	return e.executeRender(w, name, data, opts...)
+	return e.executeRender(w, name, data, newRenderState(), opts...)
+}
+
+// ContentType get the content type of rendered output
+func (e *ViewEngine) ContentType() []string {
+	if e.config.TextMode {
+		return TextContentType
+	}
+	return HTMLContentType
 }
 
 // RenderWriter render template with io.Writer
 func (e *ViewEngine) RenderWriter(w io.Writer, name string, data any, opts ...RenderOption) error {
-	return e.executeRender(w, name, data, opts...)
+	return e.executeRender(w, name, data, newRenderState(), opts...)
 }
 
-func (e *ViewEngine) executeRender(out io.Writer, name string, data any, opts ...RenderOption) error {
+func (e *ViewEngine) executeRender(out io.Writer, name string, data any, state *renderState, opts ...RenderOption) error {
 	useMaster := true
 	if filepath.Ext(name) == e.config.Extension {
 		useMaster = false
 		name = strings.TrimSuffix(name, e.config.Extension)
 
 	}
-	return e.executeTemplate(out, name, data, useMaster, opts...)
+	if e.config.Minify && !e.config.TextMode {
+		buf := new(bytes.Buffer)
+		if err := e.executeMeasured(buf, name, data, useMaster, state, opts...); err != nil {
+			return err
+		}
+		return e.getMinifier().Minify("text/html", out, buf)
+	}
+	return e.executeMeasured(out, name, data, useMaster, state, opts...)
 }
 
-func (e *ViewEngine) executeTemplate(out io.Writer, name string, data any, useMaster bool, opts ...RenderOption) error {
-	var tpl *template.Template
+// executeMeasured execute the top-level template, observing its duration
+func (e *ViewEngine) executeMeasured(out io.Writer, name string, data any, useMaster bool, state *renderState, opts ...RenderOption) error {
+	if e.metrics == nil && e.config.SlowRender <= 0 {
+		return state.renderError(name, e.executeTemplate(out, name, data, useMaster, state, opts...))
+	}
+	start := time.Now()
+	err := e.executeTemplate(out, name, data, useMaster, state, opts...)
+	elapsed := time.Since(start)
+	if e.metrics != nil {
+		e.metrics.ObserveRender(name, elapsed, err)
+	}
+	if e.config.SlowRender > 0 && elapsed >= e.config.SlowRender {
+		e.logger.Warn("goview: slow template render", "template", name, "duration", elapsed, "threshold", e.config.SlowRender)
+	}
+	return state.renderError(name, err)
+}
+
+func (e *ViewEngine) executeTemplate(out io.Writer, name string, data any, useMaster bool, state *renderState, opts ...RenderOption) error {
+	var tpl viewTemplate
 	var err error
 	var ok bool
 
+	start := time.Now()
+	if err = state.enter(name, e.config.MaxIncludeDepth); err != nil {
+		return err
+	}
+	defer state.leave()
+
+	renderCtx := e.newRenderContext(name, data, useMaster, state, opts)
+	if state.root == nil {
+		state.root = renderCtx
+	}
+
+	if trace := renderCtx.Trace; trace != nil {
+		span := trace.begin(name, len(state.stack)-1, start)
+		cw := &countWriter{w: out}
+		out = cw
+		defer func() {
+			trace.end(span, start, cw.n, err)
+		}()
+	}
+
+	exeName := name
+	if renderCtx.UseMaster && e.config.Master != "" {
+		exeName = e.config.Master
+	}
+
+	e.tplMutex.RLock()
+	tpl, ok = e.tplMap[name]
+	e.tplMutex.RUnlock()
+
+	if e.metrics != nil {
+		e.metrics.ObserveCache(name, ok && !e.config.DisableCache)
+	}
+	if !ok || e.config.DisableCache {
+		tpl, err = e.compileGroup.do(name, func() (viewTemplate, error) {
+			return e.compile(name, renderCtx)
+		})
+		if err != nil {
+			state.fail()
+			if len(state.stack) == 1 {
+				e.logger.Error("goview: template compile failed", "template", name, "error", err)
+			}
+			return err
+		}
+	} else {
+		e.logger.Debug("goview: template cache hit", "template", name)
+	}
+
+	// Display the content to the screen
+	err = tpl.execute(out, exeName, data, renderCtx.Funcs)
+	if err != nil {
+		state.fail()
+		// Nested templates return their error to the including template, the top-level render
+		// logs and wraps it once
+		if len(state.stack) > 1 {
+			return err
+		}
+		e.logger.Error("goview: template execute failed", "template", name, "error", err)
+		return fmt.Errorf("ViewEngine execute template error: %v", err)
+	}
+
+	return nil
+}
+
+// newRenderContext new render context of the template with the builtin and configured funcs,
+// the render options applied and the sandbox enforced
+func (e *ViewEngine) newRenderContext(name string, data any, useMaster bool, state *renderState, opts []RenderOption) *RenderContext {
 	renderCtx := &RenderContext{
 		Name:      name,
 		Data:      data,
@@ -120,11 +258,108 @@ func (e *ViewEngine) executeTemplate(out io.Writer, name string, data any, useMa
 		Config:    e.config,
 		Funcs:     make(template.FuncMap, 0),
 	}
+	// include render the template without master layout, with the render data
 	renderCtx.Funcs["include"] = func(layout string) (template.HTML, error) {
 		buf := new(bytes.Buffer)
-		err := e.executeTemplate(buf, layout, data, false, opts...)
+		err := e.executeTemplate(buf, layout, data, false, state, opts...)
 		return template.HTML(buf.String()), err
 	}
+	// partial render the template without master layout, with ctx as the only data
+	renderCtx.Funcs["partial"] = func(layout string, ctx ...any) (template.HTML, error) {
+		return e.partial(layout, ctx, state, opts...)
+	}
+	// partialCached render the partial once per name and variants, see Config.PartialCacheTTL
+	renderCtx.Funcs["partialCached"] = func(layout string, ctx any, variants ...any) (template.HTML, error) {
+		return e.partialCached(layout, ctx, variants, renderCtx.Language, state, opts...)
+	}
+	// cspNonce get the CSP nonce of the render, or of the request context, see CSPMiddleware
+	renderCtx.Funcs["cspNonce"] = func() (string, error) {
+		if state.cached > 0 {
+			return "", fmt.Errorf("cspNonce can't be used in cached fragments")
+		}
+		if renderCtx.CSPNonce == "" && renderCtx.Request != nil {
+			return CSPNonceFromContext(renderCtx.Request.Context()), nil
+		}
+		return renderCtx.CSPNonce, nil
+	}
+	// request get the request namespace, read only access to the rendered request
+	renderCtx.Funcs["request"] = func() (*RequestNamespace, error) {
+		if state.cached > 0 {
+			return nil, fmt.Errorf("request can't be used in cached fragments")
+		}
+		return &RequestNamespace{r: renderCtx.Request}, nil
+	}
+	// lang get the translations namespace of the render language
+	renderCtx.Funcs["lang"] = func() *LangNamespace {
+		return e.langNamespace(renderCtx)
+	}
+	// T translate the key with the optional arguments, see lang.Translate
+	renderCtx.Funcs["T"] = func(key string, args ...any) (string, error) {
+		return e.langNamespace(renderCtx).Translate(key, args...)
+	}
+	// paginate paginate the slice with the page size and page number, and the optional URL format.
+	// Default page URLs keep the query of the request.
+	renderCtx.Funcs["paginate"] = func(items any, pageSize, page int, urlFormat ...string) (*Paginator, error) {
+		p, err := paginateFunc(items, pageSize, page, urlFormat...)
+		if err == nil && renderCtx.Request != nil && state.cached == 0 {
+			p.WithQuery(renderCtx.Request.URL.Query())
+		}
+		return p, err
+	}
+	// pagination render the builtin navigation of the paginator
+	renderCtx.Funcs["pagination"] = func(p *Paginator) (template.HTML, error) {
+		return p.Pagination()
+	}
+	// newScratch get a new scratch pad to set and add values while rendering
+	renderCtx.Funcs["newScratch"] = NewScratch
+	// humanize get the namespace formatting numbers and sizes for humans
+	renderCtx.Funcs["humanize"] = func() *HumanizeNamespace {
+		return humanizeNamespace
+	}
+	// inflect get the namespace pluralizing and singularizing words
+	renderCtx.Funcs["inflect"] = func() *InflectNamespace {
+		return inflectNamespace
+	}
+	// strconv get the namespace quoting, parsing and formatting values
+	renderCtx.Funcs["strconv"] = func() *StrconvNamespace {
+		return strconvNamespace
+	}
+	// uuid get the namespace generating and parsing UUIDs
+	renderCtx.Funcs["uuid"] = func() *UUIDNamespace {
+		return uuidNamespace
+	}
+	// resources get the asset pipeline namespace, see SetResources
+	renderCtx.Funcs["resources"] = func() *ResourcesNamespace {
+		return &ResourcesNamespace{e: e}
+	}
+	// images get the namespace processing image resources
+	renderCtx.Funcs["images"] = func() *ImagesNamespace {
+		return &ImagesNamespace{e: e}
+	}
+	// data get the namespace fetching remote JSON and CSV data, see SetDataOptions
+	renderCtx.Funcs["data"] = func() *DataNamespace {
+		return &DataNamespace{e: e}
+	}
+	// os get the namespace reading files of the content root, see SetContentFS
+	renderCtx.Funcs["os"] = func() *OSNamespace {
+		return &OSNamespace{e: e}
+	}
+	// minifyTrustedCSS minify the css with the engine minifier and mark it as safe template.CSS, only for
+	// trusted css, never for user input
+	renderCtx.Funcs["minifyTrustedCSS"] = func(s string) (template.CSS, error) {
+		out, err := e.minifyString("text/css", s)
+		return template.CSS(out), err
+	}
+	// minifyTrustedJS minify the javascript with the engine minifier and mark it as safe template.JS, only
+	// for trusted javascript, never for user input
+	renderCtx.Funcs["minifyTrustedJS"] = func(s string) (template.JS, error) {
+		out, err := e.minifyString("text/javascript", s)
+		return template.JS(out), err
+	}
+	// cache render the partial once and cache the output for ttl in the fragment store
+	renderCtx.Funcs["cache"] = func(name string, ttl any, layout string, ctx any) (template.HTML, error) {
+		return e.fragment(name, ttl, layout, ctx, renderCtx.Language, state, opts...)
+	}
 
 	// Get the plugin collection
 	for k, v := range e.config.Funcs {
@@ -133,58 +368,57 @@ func (e *ViewEngine) executeTemplate(out io.Writer, name string, data any, useMa
 	for _, opt := range opts {
 		opt(renderCtx)
 	}
+	sandboxFuncs(renderCtx.Funcs, e.config)
+	return renderCtx
+}
 
-	e.tplMutex.RLock()
-	tpl, ok = e.tplMap[name]
-	e.tplMutex.RUnlock()
-
-	exeName := name
-	if renderCtx.UseMaster && e.config.Master != "" {
-		exeName = e.config.Master
+// compile parse the template with its master and partials, and store it in the cache
+func (e *ViewEngine) compile(name string, renderCtx *RenderContext) (viewTemplate, error) {
+	if !e.config.DisableCache {
+		// Another caller may have finished compiling while we were waiting
+		e.tplMutex.RLock()
+		tpl, ok := e.tplMap[name]
+		e.tplMutex.RUnlock()
+		if ok {
+			return tpl, nil
+		}
 	}
 
-	if !ok || e.config.DisableCache {
-		tplList := make([]string, 0)
-		if renderCtx.UseMaster {
-			//render()
-			if e.config.Master != "" {
-				tplList = append(tplList, e.config.Master)
-			}
+	tplList := make([]string, 0)
+	if renderCtx.UseMaster {
+		//render()
+		if e.config.Master != "" {
+			tplList = append(tplList, e.config.Master)
 		}
-		tplList = append(tplList, name)
-		tplList = append(tplList, e.config.Partials...)
-
-		// Loop through each template and test the full path
-		tpl = template.New(name).Funcs(renderCtx.Funcs).Delims(e.config.Delims.Left, e.config.Delims.Right)
-		for _, v := range tplList {
-			var data string
-			data, err = e.fileHandler(e.config, v)
-			if err != nil {
-				return err
-			}
-			var tmpl *template.Template
-			if v == name {
-				tmpl = tpl
-			} else {
-				tmpl = tpl.New(v)
-			}
-			_, err = tmpl.Parse(data)
-			if err != nil {
-				return fmt.Errorf("ViewEngine render parser name:%v, error: %v", v, err)
-			}
-		}
-		e.tplMutex.Lock()
-		e.tplMap[name] = tpl
-		e.tplMutex.Unlock()
 	}
+	tplList = append(tplList, name)
+	tplList = append(tplList, e.config.Partials...)
 
-	// Display the content to the screen
-	err = tpl.Funcs(renderCtx.Funcs).ExecuteTemplate(out, exeName, data)
+	// Loop through each template and test the full path
+	tpl, err := newViewTemplate(name, e.config, renderCtx.Funcs)
 	if err != nil {
-		return fmt.Errorf("ViewEngine execute template error: %v", err)
+		return nil, err
 	}
-
-	return nil
+	for _, v := range tplList {
+		data, err := e.fileHandler(e.config, v)
+		if err != nil {
+			return nil, err
+		}
+		err = tpl.parse(v, data)
+		if err != nil {
+			return nil, fmt.Errorf("ViewEngine render parser name:%v, error: %v", v, err)
+		}
+	}
+	e.tplMutex.Lock()
+	_, reloaded := e.tplMap[name]
+	e.tplMap[name] = tpl
+	e.tplMutex.Unlock()
+	if reloaded {
+		e.logger.Debug("goview: template reloaded", "template", name)
+	} else {
+		e.logger.Debug("goview: template compiled", "template", name)
+	}
+	return tpl, nil
 }
 
 // SetFileHandler set file handler
@@ -2,8 +2,14 @@ package goview
 
 import (
 	"bytes"
+	"expvar"
 	"html/template"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var cases = []struct {
@@ -66,3 +72,252 @@ func TestViewEngine_RenderWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestViewEngine_ConcurrentCompile(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		Partials:  []string{},
+		Funcs:     template.FuncMap{},
+	})
+
+	var reads int32
+	fileHandler := DefaultFileHandler()
+	gv.SetFileHandler(func(config Config, tplFile string) (string, error) {
+		atomic.AddInt32(&reads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return fileHandler(config, tplFile)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buff := new(bytes.Buffer)
+			if err := gv.RenderWriter(buff, "index", M{}); err != nil {
+				t.Errorf("render error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// master + index
+	if n := atomic.LoadInt32(&reads); n != 2 {
+		t.Errorf("actual reads: %v, expect: %v", n, 2)
+	}
+}
+
+func TestViewEngine_Metrics(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+		Partials:  []string{},
+		Funcs:     template.FuncMap{},
+	})
+	metrics := NewExpvarMetrics("goview_test")
+	gv.SetMetrics(metrics)
+
+	for i := 0; i < 2; i++ {
+		if err := gv.RenderWriter(new(bytes.Buffer), "index", M{}); err != nil {
+			t.Errorf("render error: %v", err)
+		}
+	}
+	if err := gv.RenderWriter(new(bytes.Buffer), "notfound", M{}); err == nil {
+		t.Error("render notfound is ok?")
+	}
+
+	expects := map[*expvar.Map]string{
+		metrics.renders:     `{"index": 2, "notfound": 1}`,
+		metrics.errors:      `{"notfound": 1}`,
+		metrics.cacheHits:   `{"index": 1}`,
+		metrics.cacheMisses: `{"index": 1, "notfound": 1}`,
+	}
+	for m, expect := range expects {
+		if val := m.String(); val != expect {
+			t.Errorf("actual: %v, expect: %v", val, expect)
+		}
+	}
+}
+
+func TestViewEngine_Logger(t *testing.T) {
+	gv := New(Config{
+		Root:       "_examples/test",
+		Extension:  ".tpl",
+		Master:     "layouts/master",
+		Partials:   []string{},
+		Funcs:      template.FuncMap{},
+		SlowRender: time.Nanosecond,
+	})
+	logs := new(bytes.Buffer)
+	gv.SetLogger(slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if err := gv.RenderWriter(new(bytes.Buffer), "index", M{}); err != nil {
+		t.Errorf("render error: %v", err)
+	}
+	if err := gv.RenderWriter(new(bytes.Buffer), "index", M{}); err != nil {
+		t.Errorf("render error: %v", err)
+	}
+	if err := gv.RenderWriter(new(bytes.Buffer), "notfound", M{}); err == nil {
+		t.Error("render notfound is ok?")
+	}
+
+	for _, expect := range []string{
+		`msg="goview: template compiled" template=index`,
+		`msg="goview: template cache hit" template=index`,
+		`msg="goview: template compile failed" template=notfound`,
+		`msg="goview: slow template render" template=index`,
+	} {
+		if !strings.Contains(logs.String(), expect) {
+			t.Errorf("logs: %v, expect contains: %v", logs.String(), expect)
+		}
+	}
+	// Errors of nested templates are logged once, by the top-level render
+	logs.Reset()
+	if err := gv.RenderWriter(new(bytes.Buffer), "loop", M{}); err == nil {
+		t.Error("render include cycle is ok?")
+	}
+	if n := strings.Count(logs.String(), "level=ERROR"); n != 1 {
+		t.Errorf("error logs: %v, expect: 1, logs: %v", n, logs.String())
+	}
+	if n := strings.Count(logs.String(), "ViewEngine execute template error"); n != 0 {
+		t.Errorf("nested errors wrapped: %v", logs.String())
+	}
+}
+
+func TestViewEngine_StrictVariables(t *testing.T) {
+	for _, config := range []Config{
+		{StrictVariables: true},
+		{Options: []string{"missingkey=error"}},
+	} {
+		config.Root = "_examples/test"
+		config.Extension = ".tpl"
+		config.Master = "layouts/master"
+		gv := New(config)
+
+		buff := new(bytes.Buffer)
+		if err := gv.RenderWriter(buff, "missing", M{"name": "GoView"}); err != nil {
+			t.Errorf("render error: %v", err)
+		} else if val := buff.String(); val != "<v>GoView</v>" {
+			t.Errorf("actual: %v, expect: %v", val, "<v>GoView</v>")
+		}
+		if err := gv.RenderWriter(new(bytes.Buffer), "missing", M{}); err == nil {
+			t.Error("render missing key is ok?")
+		}
+	}
+
+	gv := New(Config{Root: "_examples/test", Extension: ".tpl", Options: []string{"missingkey=unknown"}})
+	if err := gv.RenderWriter(new(bytes.Buffer), "echo.tpl", M{}); err == nil {
+		t.Error("render with unknown option is ok?")
+	}
+}
+
+func TestViewEngine_TextMode(t *testing.T) {
+	for _, v := range []struct {
+		TextMode bool
+		Out      string
+	}{
+		{TextMode: false, Out: "$&lt;b&gt;Go&amp;View&lt;/b&gt;"},
+		{TextMode: true, Out: "$<b>Go&View</b>"},
+	} {
+		gv := New(Config{
+			Root:      "_examples/test",
+			Extension: ".tpl",
+			Master:    "layouts/master",
+			Funcs: template.FuncMap{
+				"echo": func(v string) string {
+					return "$" + v
+				},
+			},
+			TextMode: v.TextMode,
+		})
+		buff := new(bytes.Buffer)
+		if err := gv.RenderWriter(buff, "echo.tpl", M{"name": "<b>Go&View</b>"}); err != nil {
+			t.Errorf("render error: %v", err)
+			continue
+		}
+		if val := buff.String(); val != v.Out {
+			t.Errorf("actual: %v, expect: %v", val, v.Out)
+		}
+	}
+}
+
+func TestViewEngine_Partial(t *testing.T) {
+	gv := New(Config{
+		Root:      "_examples/test",
+		Extension: ".tpl",
+		Master:    "layouts/master",
+	})
+	buff := new(bytes.Buffer)
+	err := gv.RenderWriter(buff, "partial", M{"name": "Page", "user": M{"name": "GoView"}})
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if val := buff.String(); val != "<v>CardGoView</v>" {
+		t.Errorf("actual: %v, expect: %v", val, "<v>CardGoView</v>")
+	}
+}
+
+func TestViewEngine_PartialCached(t *testing.T) {
+	for _, v := range []struct {
+		TTL  time.Duration
+		Outs []string
+	}{
+		{TTL: 0, Outs: []string{"<v>111</v>", "<v>222</v>"}},
+		{TTL: time.Minute, Outs: []string{"<v>111</v>", "<v>111</v>"}},
+	} {
+		count := 0
+		gv := New(Config{
+			Root:      "_examples/test",
+			Extension: ".tpl",
+			Master:    "layouts/master",
+			Funcs: template.FuncMap{
+				"count": func() int {
+					count++
+					return count
+				},
+			},
+			PartialCacheTTL: v.TTL,
+		})
+		for _, out := range v.Outs {
+			buff := new(bytes.Buffer)
+			if err := gv.RenderWriter(buff, "cached", M{"items": []int{1, 2, 3}}); err != nil {
+				t.Errorf("render error: %v", err)
+				continue
+			}
+			if val := buff.String(); val != out {
+				t.Errorf("ttl: %v, actual: %v, expect: %v", v.TTL, val, out)
+			}
+		}
+
+		// Partials cached across renders are keyed by the render language
+		if v.TTL > 0 {
+			buff := new(bytes.Buffer)
+			if err := gv.RenderWriter(buff, "cached", M{"items": []int{1, 2, 3}}, WithLanguage("de")); err != nil {
+				t.Fatalf("render error: %v", err)
+			}
+			if val := buff.String(); val != "<v>222</v>" {
+				t.Errorf("language: de, actual: %v", val)
+			}
+		}
+	}
+}
+
+func TestViewEngine_MaxIncludeDepth(t *testing.T) {
+	gv := New(Config{
+		Root:            "_examples/test",
+		Extension:       ".tpl",
+		Master:          "layouts/master",
+		MaxIncludeDepth: 10,
+	})
+	err := gv.RenderWriter(new(bytes.Buffer), "loop", M{})
+	if err == nil {
+		t.Fatal("render include cycle is ok?")
+	}
+	expect := "cycle: widgets/loop-partial -> widgets/loop -> widgets/loop-partial"
+	if !strings.HasSuffix(err.Error(), expect) {
+		t.Errorf("actual: %v, expect suffix: %v", err, expect)
+	}
+}